@@ -0,0 +1,27 @@
+package mockclient
+
+// UsersAPI - The user operations of the mock API
+type UsersAPI interface {
+	GetUsers() ([]User, error)
+	GetUserByID(id int) (*User, error)
+	CreateUser(user *User) (*User, error)
+	UpdateUser(user *User) (*User, error)
+	DeleteUser(id int) error
+}
+
+// ProductsAPI - The product operations of the mock API
+type ProductsAPI interface {
+	GetProducts() ([]Product, error)
+	GetProductByID(id int) (*Product, error)
+	CreateProduct(product *Product) (*Product, error)
+	UpdateProduct(product *Product) (*Product, error)
+	DeleteProduct(id int) error
+}
+
+// API - All user and product operations
+type API interface {
+	UsersAPI
+	ProductsAPI
+}
+
+var _ API = (*Client)(nil)
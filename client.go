@@ -11,6 +11,57 @@ import (
 type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
+	// ResponseCache, when set, revalidates GET responses with If-None-Match/If-Modified-Since
+	ResponseCache *ResponseCache
+	// RecordCache, when set, serves GetUserByID/GetProductByID and is kept current by writes
+	RecordCache *RecordCache
+	// CoalesceReads shares one in-flight request between concurrent identical GETs
+	CoalesceReads bool
+	// MaxResponseBytes bounds the size of a response body; zero means unlimited
+	MaxResponseBytes int64
+	// GzipRequests compresses write request bodies with Content-Encoding: gzip
+	GzipRequests bool
+	// AcceptGzip asks for gzip responses and decompresses them
+	AcceptGzip bool
+	// DiskCache, when set, keeps a copy of every successful GET response on disk
+	DiskCache *DiskCache
+	// Offline serves reads from DiskCache and fails writes with ErrOffline instead of contacting the server
+	Offline bool
+	// OnCachedRead, when set, is called with the age of every response served from DiskCache
+	OnCachedRead func(CachedResponseInfo)
+	// Outbox, when set, records writes made while offline or while the server is unreachable
+	Outbox *Outbox
+
+	flights     flightGroup
+	compression compressionCounters
+}
+
+// DefaultMaxResponseBytes is the response size limit set by NewClient
+const DefaultMaxResponseBytes = 10 << 20
+
+// maxErrorBodyLen bounds how much of a response body is embedded in an error message
+const maxErrorBodyLen = 512
+
+// StatusError - Returned when the server responds with an unexpected status code
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Body) > maxErrorBodyLen {
+		return fmt.Sprintf("error: status: %d, body: %s... (%d more bytes)", e.StatusCode, e.Body[:maxErrorBodyLen], len(e.Body)-maxErrorBodyLen)
+	}
+	return fmt.Sprintf("error: status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// ResponseTooLargeError - Returned when a response body exceeds Client.MaxResponseBytes
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("error: response body exceeds %d bytes", e.Limit)
 }
 
 // NewClient
@@ -20,26 +71,102 @@ func NewClient(hostURL *string) (*Client, error) {
 	}
 
 	return &Client{
-		HostURL:    *hostURL,
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		HostURL:          *hostURL,
+		HTTPClient:       &http.Client{Timeout: 10 * time.Second},
+		MaxResponseBytes: DefaultMaxResponseBytes,
 	}, nil
 }
 
 // doRequest
 func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	body, _, err := c.doRequestWithHeader(req)
+	return body, err
+}
+
+// doRequestWithHeader - Like doRequest, but also returns the response headers
+func (c *Client) doRequestWithHeader(req *http.Request) ([]byte, http.Header, error) {
+	if c.Outbox != nil && req.Method != http.MethodGet {
+		return c.sendOrQueue(req)
+	}
+	if c.Offline {
+		return c.offlineResponse(req)
+	}
+
+	var body []byte
+	var header http.Header
+	var err error
+	if c.CoalesceReads && req.Method == http.MethodGet {
+		body, header, err = c.flights.do(req.URL.String(), func() ([]byte, http.Header, error) {
+			return c.send(req)
+		})
+	} else {
+		body, header, err = c.send(req)
+	}
+
+	if err == nil && req.Method == http.MethodGet && c.DiskCache != nil {
+		// A failure to persist the copy must not fail the read itself
+		c.DiskCache.store(req.URL.String(), body, header)
+	}
+
+	return body, header, err
+}
+
+// send - Performs a single request and checks its status
+func (c *Client) send(req *http.Request) ([]byte, http.Header, error) {
+	cacheable := c.ResponseCache != nil && req.Method == http.MethodGet
+	if cacheable {
+		c.ResponseCache.prepare(req)
+	}
+	if err := c.prepareCompression(req); err != nil {
+		return nil, nil, err
+	}
+
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	reader, err := c.decompress(res)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	body, err := c.readBody(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.compression.responseDecoded.Add(int64(len(body)))
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		if cacheable && res.StatusCode == http.StatusOK {
+			c.ResponseCache.store(req, body, res.Header)
+		}
+		return body, res.Header, nil
+	case http.StatusNotModified:
+		if cacheable {
+			if entry, ok := c.ResponseCache.lookup(req); ok {
+				return append([]byte(nil), entry.body...), entry.header.Clone(), nil
+			}
+		}
+	}
+
+	return nil, nil, &StatusError{StatusCode: res.StatusCode, Body: body}
+}
+
+// readBody - Reads a response body, failing once it exceeds MaxResponseBytes
+func (c *Client) readBody(r io.Reader) ([]byte, error) {
+	if c.MaxResponseBytes <= 0 {
+		return io.ReadAll(r)
 	}
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusNoContent {
-		return nil, fmt.Errorf("error: status: %d, body: %s", res.StatusCode, body)
+	body, err := io.ReadAll(io.LimitReader(r, c.MaxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.MaxResponseBytes {
+		return nil, &ResponseTooLargeError{Limit: c.MaxResponseBytes}
 	}
 
 	return body, nil
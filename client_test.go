@@ -3,8 +3,10 @@
 package mockclient
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -98,3 +100,36 @@ func TestDoRequest_InvalidBody(t *testing.T) {
 		t.Errorf("expected body to be %q, got %q", expectedBody, string(body))
 	}
 }
+
+// TestDoRequest_ResponseTooLarge tests doRequest with a body over MaxResponseBytes
+func TestDoRequest_ResponseTooLarge(t *testing.T) {
+	// Create a mock server with a large body
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.MaxResponseBytes = 1024
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	_, err := client.doRequest(req)
+
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected ResponseTooLargeError, got %v", err)
+	}
+	if tooLarge.Limit != 1024 {
+		t.Errorf("expected limit to be 1024, got %d", tooLarge.Limit)
+	}
+}
+
+// TestStatusError_TruncatesBody tests that long error bodies are shortened in the message
+func TestStatusError_TruncatesBody(t *testing.T) {
+	err := &StatusError{StatusCode: 500, Body: []byte(strings.Repeat("x", maxErrorBodyLen+100))}
+
+	expectedError := "error: status: 500, body: " + strings.Repeat("x", maxErrorBodyLen) + "... (100 more bytes)"
+	if err.Error() != expectedError {
+		t.Errorf("expected error message %q, got %q", expectedError, err.Error())
+	}
+}
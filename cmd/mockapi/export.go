@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// runExport - Streams a collection to a file, resuming an export to the same file that failed,
+// or to stdout when the file is "-"
+func runExport(client *mockclient.Client, res *resource, args []string, columns []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", string(mockclient.FormatNDJSON), "file format: csv, ndjson or json")
+	pageSize := fs.Int("page-size", mockclient.DefaultExportPageSize, "records requested per page")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("%w: export needs a file (- for stdout)", errUsage)
+	}
+	path := fs.Arg(0)
+
+	where, err := parseConditions(res, fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	opts := mockclient.ExportOptions{Format: mockclient.Format(*format), Columns: columns, PageSize: *pageSize}
+	if path != "-" {
+		opts.Checkpoint = func(cp mockclient.ExportCheckpoint) error {
+			fmt.Fprintf(stderr, "page %d: %d record(s) written\n", cp.NextPage-1, cp.Records)
+			return nil
+		}
+	}
+
+	cp, err := res.exportTo(client, path, stdout, opts, where)
+	if err != nil {
+		if cp != nil && path != "-" {
+			return fmt.Errorf("%w (run the same command again to resume from page %d)", err, cp.NextPage)
+		}
+		return err
+	}
+	if path != "-" {
+		fmt.Fprintf(stderr, "exported %d %s record(s) to %s\n", cp.Records, res.name, path)
+	}
+	return nil
+}
+
+// parseConditions - Parses "field=value" arguments, checking the fields exist on the resource
+func parseConditions(res *resource, args []string) (map[string]string, error) {
+	columns := mockclient.ColumnNames(res.sample)
+	conditions := map[string]string{}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: expected field=value, got %q", errUsage, arg)
+		}
+		if !contains(columns, name) {
+			return nil, fmt.Errorf("%w: unknown %s field %q", errUsage, res.name, name)
+		}
+		conditions[name] = value
+	}
+	return conditions, nil
+}
+
+// contains - Reports whether values holds s
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+// export_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+	"github.com/carlosrv999/mockapiclient/mockclienttest"
+)
+
+// TestRun_Export tests exporting filtered, projected products to a file and to stdout
+func TestRun_Export(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+	server.SeedProducts(
+		mockclient.Product{Name: "Ball", Department: "Toys"},
+		mockclient.Product{Name: "Lamp", Department: "Home"},
+		mockclient.Product{Name: "Kite", Department: "Toys"},
+	)
+
+	path := filepath.Join(t.TempDir(), "toys.csv")
+	code, _, errOut := runCLI(server.URL, "", "-columns", "id,name", "products", "export", "-format", "csv", "-page-size", "2", path, "department=Toys")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "id,name\n1,Ball\n3,Kite\n" {
+		t.Errorf("expected the toys, got %q", data)
+	}
+	if !strings.Contains(errOut, "exported 2 product record(s)") {
+		t.Errorf("expected a summary, got %s", errOut)
+	}
+
+	code, out, _ := runCLI(server.URL, "", "-columns", "name", "products", "export", "-", "name=Lamp")
+	if code != exitOK || out != "{\"name\":\"Lamp\"}\n" {
+		t.Errorf("expected NDJSON on stdout, got %d %q", code, out)
+	}
+
+	// A checkpoint left by an export of other records is not resumed
+	checkpoint := `{"resource":"product","format":"csv","columns":["id","name"],"filter":"{\"department\":\"Toys\"}","pageSize":2,"nextPage":2,"records":1,"bytes":13}`
+	os.WriteFile(path, []byte("id,name\n1,Ball\n"), 0o644)
+	os.WriteFile(path+".checkpoint", []byte(checkpoint), 0o644)
+	code, _, errOut = runCLI(server.URL, "", "-columns", "id,name", "products", "export", "-format", "csv", "-page-size", "2", path, "department=Home")
+	if code != exitError || !strings.Contains(errOut, "checkpoint does not match") {
+		t.Errorf("expected a checkpoint mismatch, got %d: %s", code, errOut)
+	}
+
+	if code, _, _ = runCLI(server.URL, "", "products", "export", "-", "color=red"); code != exitUsage {
+		t.Errorf("expected exit code %d for an unknown field, got %d", exitUsage, code)
+	}
+}
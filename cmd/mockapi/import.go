@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// runImport - Imports a CSV or NDJSON file, printing each row's outcome and a summary to stderr
+func runImport(client *mockclient.Client, res *resource, args []string, stdin io.Reader, stderr io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "", "file format: csv or ndjson")
+	upsert := fs.Bool("upsert", false, "update records with the same key")
+	dryRun := fs.Bool("dry-run", false, "report what would change without writing")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: import takes exactly one file", errUsage)
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = formatFromPath(path)
+	}
+	if *format != string(mockclient.FormatCSV) && *format != string(mockclient.FormatNDJSON) {
+		return fmt.Errorf("%w: unsupported import format %q", errUsage, *format)
+	}
+
+	in := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	opts := mockclient.ImportOptions{
+		DryRun: *dryRun,
+		Progress: func(p mockclient.ImportProgress) {
+			if p.Err != nil {
+				fmt.Fprintf(stderr, "[%d/%d] line %d: failed: %v\n", p.Done, p.Total, p.Line, p.Err)
+			} else {
+				fmt.Fprintf(stderr, "[%d/%d] line %d: %s\n", p.Done, p.Total, p.Line, p.Action)
+			}
+		},
+	}
+	report, err := res.importFrom(client, in, mockclient.Format(*format), opts, *upsert)
+	var verr *mockclient.ValidationError
+	if errors.As(err, &verr) {
+		return fmt.Errorf("%w: %s: %v", errUsage, path, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	note := ""
+	if report.DryRun {
+		note = " (dry run, nothing written)"
+	}
+	fmt.Fprintf(stderr, "%d row(s): %d created, %d updated, %d unchanged, %d failed%s\n",
+		report.Total, report.Created, report.Updated, report.Unchanged, len(report.Failed), note)
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d row(s) failed", len(report.Failed))
+	}
+	return nil
+}
+
+// formatFromPath - Guesses the import format from a file extension, defaulting to CSV
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return string(mockclient.FormatNDJSON)
+	}
+	return string(mockclient.FormatCSV)
+}
@@ -0,0 +1,64 @@
+// import_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+	"github.com/carlosrv999/mockapiclient/mockclienttest"
+)
+
+// TestRun_Import tests importing products from CSV with a dry run and an upsert
+func TestRun_Import(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+	server.SeedProducts(mockclient.Product{Name: "Ball", Price: 5, Stock: 10, Department: "Toys"})
+
+	path := filepath.Join(t.TempDir(), "products.csv")
+	os.WriteFile(path, []byte("name,price,stock,department\nBall,5,4,Toys\nKite,12.5,3,Toys\n"), 0o644)
+
+	code, _, errOut := runCLI(server.URL, "", "products", "import", "-upsert", "-dry-run", path)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if !strings.Contains(errOut, "2 row(s): 1 created, 1 updated, 0 unchanged, 0 failed (dry run, nothing written)") {
+		t.Errorf("expected dry run summary, got %s", errOut)
+	}
+	if len(server.Products()) != 1 {
+		t.Fatalf("expected the dry run not to write")
+	}
+
+	code, _, errOut = runCLI(server.URL, "", "products", "import", "-upsert", path)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if !strings.Contains(errOut, "[2/2] line 3: created") {
+		t.Errorf("expected per-row progress, got %s", errOut)
+	}
+	products := server.Products()
+	if len(products) != 2 || products[0].Stock != 4 || products[1].Name != "Kite" {
+		t.Errorf("expected Ball updated and Kite created, got %+v", products)
+	}
+}
+
+// TestRun_ImportInvalid tests that invalid rows are listed and nothing is written
+func TestRun_ImportInvalid(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+
+	input := `{"name": "Alice"}` + "\n" + `{"lastName": "Smith"}` + "\n"
+	code, _, errOut := runCLI(server.URL, input, "users", "import", "-format", "ndjson", "-")
+	if code != exitUsage {
+		t.Errorf("expected exit code %d, got %d", exitUsage, code)
+	}
+	if !strings.Contains(errOut, "line 2: name is required") {
+		t.Errorf("expected the invalid line in the output, got %s", errOut)
+	}
+	if len(server.Users()) != 0 {
+		t.Errorf("expected nothing to be written")
+	}
+}
@@ -0,0 +1,344 @@
+// Command mockapi manages the users and products of a mock API from the command line.
+//
+//	mockapi [options] users list
+//	mockapi [options] users get <id>
+//	mockapi [options] users create [-name ...] [-f file|-]
+//	mockapi [options] users update <id> [-name ...] [-f file|-]
+//	mockapi [options] users delete <id>
+//
+// and the same for products. The host defaults to $MOCKAPI_HOST. Field values come from flags
+// named after the JSON fields, or from a JSON object in a file (-f path, or -f - for stdin);
+// flags win over the file. Results are written as JSON unless -o selects table, ndjson, csv
+// or yaml; -columns, -sort and -template shape the output as described by mockclient.Render.
+//
+// "mockapi shell" starts an interactive session; type help there for its commands.
+//
+// Records are imported in bulk from CSV or NDJSON with "import" and streamed out with "export";
+// see the usage text.
+//
+// Exit codes: 0 success, 1 other errors, 2 invalid usage or input, 3 record not found,
+// 4 server error (5xx).
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitServerError = 4
+)
+
+// errUsage - Wraps errors caused by invalid arguments or input
+var errUsage = errors.New("invalid input")
+
+const usage = `usage: mockapi [options] <users|products> <command> [arguments]
+       mockapi [options] shell
+
+options:
+  -host URL               base URL of the API (default $MOCKAPI_HOST)
+  -o format               output format: json, table, ndjson, csv or yaml (default json)
+  -columns a,b,c          fields to output, by JSON name
+  -sort field             sort by a field, descending with a leading -
+  -template text          output each record with a text/template instead
+
+commands:
+  list                    list all records
+  get <id>                show one record
+  create [fields]         create a record
+  update <id> [fields]    change the given fields of a record
+  delete <id>             delete a record
+  import [flags] <file>   create records from a CSV or NDJSON file (- for stdin)
+      -format csv|ndjson  file format (default from the file extension, csv for stdin)
+      -upsert             update records with the same name (users: name and lastName,
+                          products: name and department) instead of creating duplicates
+      -dry-run            validate and report what would change without writing
+  export [flags] <file> [field=value ...]
+                          stream matching records to a file (- for stdout) a page at a
+                          time; a failed export to a file resumes when run again
+      -format csv|ndjson|json  file format (default ndjson)
+      -page-size n        records requested per page (default 100)
+
+fields are set with -<field> value flags or -f file.json (-f - reads stdin)
+user fields:    name, lastName, address, favoriteDogBreed
+product fields: name, price, stock, type, department
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run - Executes the command line in args and returns the exit code
+func run(args []string, getenv func(string) string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("mockapi", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+	host := fs.String("host", getenv("MOCKAPI_HOST"), "base URL of the API")
+	format := fs.String("o", string(mockclient.FormatJSON), "output format")
+	columns := fs.String("columns", "", "comma-separated fields to output")
+	sortBy := fs.String("sort", "", "field to sort by")
+	tmpl := fs.String("template", "", "text/template for each record")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	interactive := fs.NArg() == 1 && fs.Arg(0) == "shell"
+	if fs.NArg() < 2 && !interactive {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	if *host == "" {
+		fmt.Fprintln(stderr, "mockapi: no host: use -host or set MOCKAPI_HOST")
+		return exitUsage
+	}
+
+	if interactive {
+		client, err := mockclient.NewClient(host)
+		if err != nil {
+			fmt.Fprintf(stderr, "mockapi: %v\n", err)
+			return exitError
+		}
+		// The shell picks a format per result unless one was asked for
+		var shellFormat mockclient.Format
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "o" {
+				shellFormat = mockclient.Format(*format)
+			}
+		})
+		if shellFormat != "" {
+			if err := mockclient.Render(io.Discard, []mockclient.User{}, mockclient.RenderOptions{Format: shellFormat}); err != nil {
+				fmt.Fprintf(stderr, "mockapi: %v\n", err)
+				return exitUsage
+			}
+		}
+		return runShell(client, shellFormat, stdin, stdout)
+	}
+
+	res, ok := resources[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "mockapi: unknown resource %q (want users or products)\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	opts := mockclient.RenderOptions{Format: mockclient.Format(*format), SortBy: *sortBy, Template: *tmpl}
+	if *columns != "" {
+		opts.Columns = strings.Split(*columns, ",")
+	}
+	// Check the output options before running the command, so a typo does not lose the result of a write
+	if err := mockclient.Render(io.Discard, res.sample, opts); err != nil {
+		fmt.Fprintf(stderr, "mockapi: %v\n", err)
+		return exitUsage
+	}
+
+	client, err := mockclient.NewClient(host)
+	if err != nil {
+		fmt.Fprintf(stderr, "mockapi: %v\n", err)
+		return exitError
+	}
+
+	if fs.Arg(1) == "export" {
+		// Export streams its own output instead of returning records to render
+		if err := runExport(client, res, fs.Args()[2:], opts.Columns, stdout, stderr); err != nil {
+			fmt.Fprintf(stderr, "mockapi: %v\n", err)
+			return exitCode(err)
+		}
+		return exitOK
+	}
+
+	result, err := execute(client, res, fs.Arg(1), fs.Args()[2:], stdin, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "mockapi: %v\n", err)
+		return exitCode(err)
+	}
+	if result != nil {
+		if err := mockclient.Render(stdout, result, opts); err != nil {
+			fmt.Fprintf(stderr, "mockapi: %v\n", err)
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+// execute - Runs one command against a resource, returning what to print
+func execute(client *mockclient.Client, res *resource, command string, args []string, stdin io.Reader, stderr io.Writer) (interface{}, error) {
+	var api mockclient.API = client
+	switch command {
+	case "list":
+		if len(args) > 0 {
+			return nil, fmt.Errorf("%w: list takes no arguments", errUsage)
+		}
+		return res.list(api)
+
+	case "get", "delete":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%w: %s takes exactly one id", errUsage, command)
+		}
+		id, err := parseID(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if command == "delete" {
+			if err := res.delete(api, id); err != nil {
+				return nil, notFound(err, res.name, id)
+			}
+			return nil, nil
+		}
+		record, err := res.get(api, id)
+		return record, notFound(err, res.name, id)
+
+	case "create":
+		fields, rest, err := parseFields(res, command, args, stdin, stderr)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("%w: unexpected argument %q", errUsage, rest[0])
+		}
+		return res.create(api, fields)
+
+	case "update":
+		// Accept the id before or after the field flags
+		var idArg string
+		if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+			idArg, args = args[0], args[1:]
+		}
+		fields, rest, err := parseFields(res, command, args, stdin, stderr)
+		if err != nil {
+			return nil, err
+		}
+		if idArg == "" && len(rest) > 0 {
+			idArg, rest = rest[0], rest[1:]
+		}
+		if idArg == "" || len(rest) > 0 {
+			return nil, fmt.Errorf("%w: update takes exactly one id", errUsage)
+		}
+		id, err := parseID(idArg)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%w: nothing to update", errUsage)
+		}
+		record, err := res.update(client, id, fields)
+		return record, notFound(err, res.name, id)
+
+	case "import":
+		return nil, runImport(client, res, args, stdin, stderr)
+	}
+
+	return nil, fmt.Errorf("%w: unknown command %q", errUsage, command)
+}
+
+// parseFields - Parses field flags and -f input for create and update, returning the remaining arguments
+func parseFields(res *resource, command string, args []string, stdin io.Reader, stderr io.Writer) (map[string]string, []string, error) {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("f", "", "read fields from a JSON `file` (- for stdin)")
+	values := map[string]*string{}
+	for _, field := range res.fields {
+		values[field] = fs.String(field, "", "set "+field)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	fields := map[string]string{}
+	if *file != "" {
+		var err error
+		if fields, err = readFields(res, *file, stdin); err != nil {
+			return nil, nil, err
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if v, ok := values[f.Name]; ok {
+			fields[f.Name] = *v
+		}
+	})
+	return fields, fs.Args(), nil
+}
+
+// readFields - Reads a JSON object of field values from path, or from stdin when path is "-"
+func readFields(res *resource, path string, stdin io.Reader) (map[string]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", errUsage, path, err)
+	}
+
+	fields := map[string]string{}
+	for k, v := range raw {
+		if k == "id" || k == "createdAt" {
+			continue
+		}
+		if !res.hasField(k) {
+			return nil, fmt.Errorf("%w: %s: unknown %s field %q", errUsage, path, res.name, k)
+		}
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+		case float64:
+			fields[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%w: %s: field %q must be a string or number", errUsage, path, k)
+		}
+	}
+	return fields, nil
+}
+
+// parseID - Parses a record ID argument
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("%w: invalid id %q", errUsage, s)
+	}
+	return id, nil
+}
+
+// notFound - Replaces a 404 error with a message naming the record
+func notFound(err error, name string, id int) error {
+	var statusErr *mockclient.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %d not found: %w", name, id, err)
+	}
+	return err
+}
+
+// exitCode - Maps an error to the process exit code
+func exitCode(err error) int {
+	var statusErr *mockclient.StatusError
+	switch {
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		return exitNotFound
+	case errors.As(err, &statusErr) && statusErr.StatusCode >= 500:
+		return exitServerError
+	}
+	return exitError
+}
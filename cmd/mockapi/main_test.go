@@ -0,0 +1,191 @@
+// main_test.go
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+	"github.com/carlosrv999/mockapiclient/mockclienttest"
+)
+
+// runCLI - Runs the command with MOCKAPI_HOST set to host and returns its exit code and output
+func runCLI(host, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	getenv := func(key string) string {
+		if key == "MOCKAPI_HOST" {
+			return host
+		}
+		return ""
+	}
+	code := run(args, getenv, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestRun_Users tests the users commands against the fake server
+func TestRun_Users(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+
+	code, out, errOut := runCLI(server.URL, "", "users", "create", "-name", "Alice", "-lastName", "Smith")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if !strings.Contains(out, `"name": "Alice"`) {
+		t.Errorf("expected created user in output, got %s", out)
+	}
+
+	code, _, errOut = runCLI(server.URL, "", "users", "update", "1", "-address", "1 Main St")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if users := server.Users(); len(users) != 1 || users[0].Address != "1 Main St" || users[0].Name != "Alice" {
+		t.Errorf("expected updated user, got %+v", users)
+	}
+
+	code, out, _ = runCLI(server.URL, "", "users", "list")
+	if code != exitOK || !strings.Contains(out, `"address": "1 Main St"`) {
+		t.Errorf("expected user list, got %d %s", code, out)
+	}
+
+	// An empty value clears the field and leaves the others alone
+	code, _, errOut = runCLI(server.URL, "", "users", "update", "1", "-address", "")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if users := server.Users(); len(users) != 1 || users[0].Address != "" || users[0].LastName != "Smith" {
+		t.Errorf("expected the address to be cleared, got %+v", users)
+	}
+
+	if code, _, _ = runCLI(server.URL, "", "users", "delete", "1"); code != exitOK {
+		t.Errorf("expected exit code %d, got %d", exitOK, code)
+	}
+	if len(server.Users()) != 0 {
+		t.Errorf("expected user to be deleted")
+	}
+}
+
+// TestRun_ProductFieldsFromInput tests reading fields from a file and stdin, with flags taking precedence
+func TestRun_ProductFieldsFromInput(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "product.json")
+	os.WriteFile(path, []byte(`{"name": "Ball", "price": 5.5, "stock": "10", "department": "Toys"}`), 0o644)
+
+	if code, _, errOut := runCLI(server.URL, "", "products", "create", "-f", path, "-stock", "3"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if code, _, errOut := runCLI(server.URL, `{"price": "7.25"}`, "products", "update", "-f", "-", "1"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+
+	products := server.Products()
+	expected := mockclient.Product{ID: 1, Name: "Ball", Price: 7.25, Stock: 3, Department: "Toys"}
+	if len(products) != 1 {
+		t.Fatalf("expected 1 product, got %+v", products)
+	}
+	products[0].CreatedAt = ""
+	if products[0] != expected {
+		t.Errorf("expected %+v, got %+v", expected, products[0])
+	}
+}
+
+// TestRun_UpdateSendsOnlyGivenFields tests that update is a single PATCH of the given fields
+func TestRun_UpdateSendsOnlyGivenFields(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Ball", "price": "7.25", "stock": "3"}`))
+	}))
+	defer server.Close()
+
+	if code, _, errOut := runCLI(server.URL, "", "products", "update", "1", "-price", "7.25"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	expected := `PATCH /products/1 {"price":"7.250000"}`
+	if len(requests) != 1 || requests[0] != expected {
+		t.Errorf("expected only %s, got %v", expected, requests)
+	}
+
+	if code, _, _ := runCLI(server.URL, "", "products", "update", "1", "-stock", ""); code != exitUsage {
+		t.Errorf("expected exit code %d for an empty stock, got %d", exitUsage, code)
+	}
+}
+
+// TestRun_ExitCodes tests the exit codes for usage errors, missing records and server errors
+func TestRun_ExitCodes(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name string
+		host string
+		args []string
+		code int
+	}{
+		{"no host", "", []string{"users", "list"}, exitUsage},
+		{"unknown resource", server.URL, []string{"orders", "list"}, exitUsage},
+		{"unknown command", server.URL, []string{"users", "purge"}, exitUsage},
+		{"bad id", server.URL, []string{"users", "get", "abc"}, exitUsage},
+		{"unknown field", server.URL, []string{"users", "create", "-color", "red"}, exitUsage},
+		{"invalid price", server.URL, []string{"products", "create", "-price", "cheap"}, exitUsage},
+		{"not found", server.URL, []string{"products", "get", "42"}, exitNotFound},
+		{"server error", failing.URL, []string{"users", "list"}, exitServerError},
+		{"host flag", "", []string{"-host", server.URL, "users", "list"}, exitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, errOut := runCLI(tt.host, "", tt.args...)
+			if code != tt.code {
+				t.Errorf("expected exit code %d, got %d: %s", tt.code, code, errOut)
+			}
+		})
+	}
+}
+
+// TestRun_OutputOptions tests the output format, column and sort options
+func TestRun_OutputOptions(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+	server.SeedProducts(
+		mockclient.Product{Name: "Ball", Price: 5, Stock: 10},
+		mockclient.Product{Name: "Kite", Price: 12.5, Stock: 2},
+	)
+
+	code, out, errOut := runCLI(server.URL, "", "-o", "csv", "-columns", "name,price", "-sort", "-price", "products", "list")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if expected := "name,price\nKite,12.5\nBall,5\n"; out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+
+	code, out, _ = runCLI(server.URL, "", "-template", "{{.Name}}: {{.Stock}}", "products", "get", "2")
+	if code != exitOK || out != "Kite: 2\n" {
+		t.Errorf("expected templated output, got %d %q", code, out)
+	}
+
+	for _, args := range [][]string{{"-o", "xml"}, {"-columns", "color"}} {
+		code, _, _ = runCLI(server.URL, "", append(args, "products", "create", "-name", "Doll")...)
+		if code != exitUsage {
+			t.Errorf("%v: expected exit code %d, got %d", args, exitUsage, code)
+		}
+	}
+	if len(server.Products()) != 2 {
+		t.Errorf("expected invalid output options to stop the command before it runs")
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// resource - The operations of one API collection, with records passed as interface{} values
+type resource struct {
+	name   string
+	sample interface{}
+	fields []string
+	list   func(api mockclient.API) (interface{}, error)
+	get    func(api mockclient.API, id int) (interface{}, error)
+	create func(api mockclient.API, fields map[string]string) (interface{}, error)
+	// update sets only the given fields, so changes made to other fields since they were read are kept
+	update func(c *mockclient.Client, id int, fields map[string]string) (interface{}, error)
+	delete func(api mockclient.API, id int) error
+	// importFrom reads records from r and creates them, or upserts them by the resource's natural key
+	importFrom func(c *mockclient.Client, r io.Reader, format mockclient.Format, opts mockclient.ImportOptions, upsert bool) (*mockclient.ImportReport, error)
+	// exportTo streams the records matching where to path, or to w when path is "-"
+	exportTo func(c *mockclient.Client, path string, w io.Writer, opts mockclient.ExportOptions, where map[string]string) (*mockclient.ExportCheckpoint, error)
+}
+
+// hasField - Reports whether field can be set on the resource
+func (r *resource) hasField(field string) bool {
+	for _, f := range r.fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+var users = &resource{
+	name:   "user",
+	sample: []mockclient.User{},
+	fields: []string{"name", "lastName", "address", "favoriteDogBreed"},
+	list: func(api mockclient.API) (interface{}, error) {
+		return api.GetUsers()
+	},
+	get: func(api mockclient.API, id int) (interface{}, error) {
+		return api.GetUserByID(id)
+	},
+	create: func(api mockclient.API, fields map[string]string) (interface{}, error) {
+		user := mockclient.User{}
+		if err := applyFields(&user, fields); err != nil {
+			return nil, err
+		}
+		return api.CreateUser(&user)
+	},
+	update: func(c *mockclient.Client, id int, fields map[string]string) (interface{}, error) {
+		wire, err := wireValues(&mockclient.User{}, fields)
+		if err != nil {
+			return nil, err
+		}
+		return c.PatchUser(id, wire)
+	},
+	delete: func(api mockclient.API, id int) error {
+		return api.DeleteUser(id)
+	},
+	importFrom: func(c *mockclient.Client, r io.Reader, format mockclient.Format, opts mockclient.ImportOptions, upsert bool) (*mockclient.ImportReport, error) {
+		if upsert {
+			opts.UserKey = mockclient.UserFullNameKey
+		}
+		return c.ImportUsers(r, format, opts)
+	},
+	exportTo: func(c *mockclient.Client, path string, w io.Writer, opts mockclient.ExportOptions, where map[string]string) (*mockclient.ExportCheckpoint, error) {
+		if len(where) > 0 {
+			opts.FilterKey = filterKey(where)
+			opts.UserFilter = func(u mockclient.User) bool {
+				ok, _ := mockclient.MatchFields(u, where)
+				return ok
+			}
+		}
+		if path == "-" {
+			return c.ExportUsers(w, opts)
+		}
+		return c.ExportUsersToFile(path, opts)
+	},
+}
+
+var products = &resource{
+	name:   "product",
+	sample: []mockclient.Product{},
+	fields: []string{"name", "price", "stock", "type", "department"},
+	list: func(api mockclient.API) (interface{}, error) {
+		return api.GetProducts()
+	},
+	get: func(api mockclient.API, id int) (interface{}, error) {
+		return api.GetProductByID(id)
+	},
+	create: func(api mockclient.API, fields map[string]string) (interface{}, error) {
+		product := mockclient.Product{}
+		if err := applyFields(&product, fields); err != nil {
+			return nil, err
+		}
+		return api.CreateProduct(&product)
+	},
+	update: func(c *mockclient.Client, id int, fields map[string]string) (interface{}, error) {
+		wire, err := wireValues(&mockclient.Product{}, fields)
+		if err != nil {
+			return nil, err
+		}
+		return c.PatchProduct(id, wire)
+	},
+	delete: func(api mockclient.API, id int) error {
+		return api.DeleteProduct(id)
+	},
+	importFrom: func(c *mockclient.Client, r io.Reader, format mockclient.Format, opts mockclient.ImportOptions, upsert bool) (*mockclient.ImportReport, error) {
+		if upsert {
+			opts.ProductKey = mockclient.ProductNameDepartmentKey
+		}
+		return c.ImportProducts(r, format, opts)
+	},
+	exportTo: func(c *mockclient.Client, path string, w io.Writer, opts mockclient.ExportOptions, where map[string]string) (*mockclient.ExportCheckpoint, error) {
+		if len(where) > 0 {
+			opts.FilterKey = filterKey(where)
+			opts.ProductFilter = func(p mockclient.Product) bool {
+				ok, _ := mockclient.MatchFields(p, where)
+				return ok
+			}
+		}
+		if path == "-" {
+			return c.ExportProducts(w, opts)
+		}
+		return c.ExportProductsToFile(path, opts)
+	},
+}
+
+// resources - The resources by command-line name
+var resources = map[string]*resource{
+	"users":    users,
+	"products": products,
+}
+
+// filterKey - Identifies a set of conditions for export checkpoints; the keys come out sorted
+func filterKey(where map[string]string) string {
+	data, _ := json.Marshal(where)
+	return string(data)
+}
+
+// wireValues - Checks fields by applying them to an empty record and returns them as the record
+// encodes them, e.g. prices in the API's format. Empty values are kept, so they clear the field.
+func wireValues(record interface{}, fields map[string]string) (map[string]string, error) {
+	if err := applyFields(record, fields); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	encoded := map[string]interface{}{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	wire := map[string]string{}
+	for k, v := range fields {
+		if s, ok := encoded[k].(string); ok {
+			v = s
+		}
+		wire[k] = v
+	}
+	return wire, nil
+}
+
+// applyFields - Sets fields, given by JSON name as strings, on a User or Product.
+// Every field travels as a string on the wire, so the values are overlaid on the record's
+// JSON encoding and decoded back, which also validates price and stock.
+func applyFields(record interface{}, fields map[string]string) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	wire := map[string]interface{}{}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	for k, v := range fields {
+		wire[k] = v
+	}
+
+	if data, err = json.Marshal(wire); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, record); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// maxHistory bounds the lines kept in memory and in the history file
+const maxHistory = 500
+
+const shellHelp = `commands:
+  list <users|products>                   list all records
+  get <user|product> <id>                 show one record
+  find <users|products> field=value ...   list the records whose fields all match
+  create <user|product> field=value ...   create a record
+  set <field> <value> on <user|product> <id>
+                                          change one field of a record
+  delete <user|product> <id>              delete a record
+  format <auto|table|json|ndjson|csv|yaml>
+                                          choose how results are printed; auto shows
+                                          lists as tables and single records as JSON
+  history                                 show previous commands
+  help                                    show this help
+  exit                                    leave the shell
+
+values containing spaces can be quoted: set name "Big Ball" on product 7
+tab completes commands, resources and fields; up and down recall history
+`
+
+// shell - An interactive session against one client
+type shell struct {
+	client  *mockclient.Client
+	out     io.Writer
+	format  mockclient.Format
+	history []string
+	// historyPath, when set, is where history is loaded from and appended to
+	historyPath string
+}
+
+// runShell - Reads and executes commands until exit or end of input
+func runShell(client *mockclient.Client, format mockclient.Format, stdin io.Reader, stdout io.Writer) int {
+	s := &shell{client: client, out: stdout, format: format}
+	if home, err := os.UserHomeDir(); err == nil {
+		s.historyPath = filepath.Join(home, ".mockapi_history")
+	}
+
+	var reader lineReader
+	if f, ok := stdin.(*os.File); ok && isTerminal(f) {
+		s.loadHistory()
+		reader = &terminalReader{in: bufio.NewReader(f), tty: f, out: stdout, shell: s}
+		fmt.Fprintf(stdout, "connected to %s; type help for commands\n", client.HostURL)
+	} else {
+		// Commands piped in are not recorded in the history file
+		s.historyPath = ""
+		reader = &plainReader{scanner: bufio.NewScanner(stdin)}
+	}
+
+	for {
+		line, err := reader.readLine("mockapi> ")
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+		if err != nil {
+			return exitOK
+		}
+		if s.exec(line) {
+			return exitOK
+		}
+	}
+}
+
+// exec - Runs one command line, printing its result or error; reports whether the shell should exit
+func (s *shell) exec(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+	s.addHistory(line)
+
+	words, err := splitWords(line)
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+		return false
+	}
+
+	switch words[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		fmt.Fprint(s.out, shellHelp)
+		return false
+	case "history":
+		for i, h := range s.history {
+			fmt.Fprintf(s.out, "%4d  %s\n", i+1, h)
+		}
+		return false
+	}
+
+	result, err := s.run(words)
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+		return false
+	}
+	if result != nil {
+		s.print(result)
+	}
+	return false
+}
+
+// run - Executes a data command, returning what to print
+func (s *shell) run(words []string) (interface{}, error) {
+	command, args := words[0], words[1:]
+	switch command {
+	case "list", "get", "delete", "find", "create":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s what? (user or product)", command)
+		}
+		res, err := shellResource(args[0])
+		if err != nil {
+			return nil, err
+		}
+		args = args[1:]
+
+		switch command {
+		case "find":
+			where, err := parseConditions(res, args)
+			if err != nil {
+				return nil, err
+			}
+			return s.find(res, where)
+		case "create":
+			fields, err := parseAssignments(res, args)
+			if err != nil {
+				return nil, err
+			}
+			return res.create(s.client, fields)
+		}
+		result, err := execute(s.client, res, command, args, nil, io.Discard)
+		if err == nil && command == "delete" {
+			fmt.Fprintf(s.out, "deleted %s %s\n", res.name, args[0])
+		}
+		return result, err
+
+	case "set":
+		// set <field> <value> on <resource> <id>
+		if len(args) != 5 || args[2] != "on" {
+			return nil, errors.New("usage: set <field> <value> on <user|product> <id>")
+		}
+		res, err := shellResource(args[3])
+		if err != nil {
+			return nil, err
+		}
+		if !res.hasField(args[0]) {
+			return nil, fmt.Errorf("unknown %s field %q", res.name, args[0])
+		}
+		id, err := parseID(args[4])
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.update(s.client, id, map[string]string{args[0]: args[1]})
+		return record, notFound(err, res.name, id)
+
+	case "format":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("format is %s", s.formatName())
+		}
+		if args[0] == "auto" {
+			s.format = ""
+			return nil, nil
+		}
+		if err := mockclient.Render(io.Discard, []mockclient.User{}, mockclient.RenderOptions{Format: mockclient.Format(args[0])}); err != nil {
+			return nil, err
+		}
+		s.format = mockclient.Format(args[0])
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown command %q (try help)", command)
+}
+
+// find - Lists the records of res whose fields all match where
+func (s *shell) find(res *resource, where map[string]string) (interface{}, error) {
+	all, err := res.list(s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	switch records := all.(type) {
+	case []mockclient.User:
+		matches := []mockclient.User{}
+		for _, u := range records {
+			if ok, _ := mockclient.MatchFields(u, where); ok {
+				matches = append(matches, u)
+			}
+		}
+		return matches, nil
+	case []mockclient.Product:
+		matches := []mockclient.Product{}
+		for _, p := range records {
+			if ok, _ := mockclient.MatchFields(p, where); ok {
+				matches = append(matches, p)
+			}
+		}
+		return matches, nil
+	}
+	return all, nil
+}
+
+// print - Renders a result in the chosen format; by default lists are tables and records are JSON
+func (s *shell) print(result interface{}) {
+	format := s.format
+	if format == "" {
+		format = mockclient.FormatJSON
+		if _, isList := result.([]mockclient.User); isList {
+			format = mockclient.FormatTable
+		}
+		if _, isList := result.([]mockclient.Product); isList {
+			format = mockclient.FormatTable
+		}
+	}
+	if err := mockclient.Render(s.out, result, mockclient.RenderOptions{Format: format}); err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+	}
+}
+
+// formatName - Describes the current output format
+func (s *shell) formatName() string {
+	if s.format == "" {
+		return "auto"
+	}
+	return string(s.format)
+}
+
+// addHistory - Records a command line, skipping immediate repeats
+func (s *shell) addHistory(line string) {
+	if n := len(s.history); n > 0 && s.history[n-1] == line {
+		return
+	}
+	s.history = append(s.history, line)
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+	}
+
+	if s.historyPath != "" {
+		if f, err := os.OpenFile(s.historyPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err == nil {
+			fmt.Fprintln(f, line)
+			f.Close()
+		}
+	}
+}
+
+// loadHistory - Reads the most recent lines of the history file
+func (s *shell) loadHistory() {
+	if s.historyPath == "" {
+		return
+	}
+	data, err := os.ReadFile(s.historyPath)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+	}
+	for _, line := range lines {
+		if line != "" {
+			s.history = append(s.history, line)
+		}
+	}
+}
+
+// shellResource - Looks up a resource by singular or plural name
+func shellResource(name string) (*resource, error) {
+	switch name {
+	case "user", "users":
+		return users, nil
+	case "product", "products":
+		return products, nil
+	}
+	return nil, fmt.Errorf("unknown resource %q (want user or product)", name)
+}
+
+// parseAssignments - Parses "field=value" arguments into settable fields of res
+func parseAssignments(res *resource, args []string) (map[string]string, error) {
+	fields := map[string]string{}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected field=value, got %q", arg)
+		}
+		if !res.hasField(name) {
+			return nil, fmt.Errorf("unknown %s field %q", res.name, name)
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// splitWords - Splits a command line on spaces, keeping single- or double-quoted text together
+func splitWords(line string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// complete - Returns the start of the word being typed at the end of line and the words that can
+// complete it, sorted
+func complete(line string) (int, []string) {
+	start := strings.LastIndexAny(line, " \t") + 1
+	prefix := line[start:]
+	before := strings.Fields(line[:start])
+
+	var options []string
+	switch {
+	case len(before) == 0:
+		options = []string{"create", "delete", "exit", "find", "format", "get", "help", "history", "list", "set"}
+	case len(before) == 1:
+		switch before[0] {
+		case "list", "find":
+			options = []string{"products", "users"}
+		case "get", "delete", "create":
+			options = []string{"product", "user"}
+		case "set":
+			options = append(append(options, users.fields...), products.fields...)
+		case "format":
+			options = []string{"auto", "csv", "json", "ndjson", "table", "yaml"}
+		}
+	case before[0] == "find" || before[0] == "create":
+		res, err := shellResource(before[1])
+		if err != nil {
+			return start, nil
+		}
+		fields := res.fields
+		if before[0] == "find" {
+			fields = mockclient.ColumnNames(res.sample)
+		}
+		for _, f := range fields {
+			options = append(options, f+"=")
+		}
+	case before[0] == "set" && len(before) == 3:
+		options = []string{"on"}
+	case before[0] == "set" && len(before) == 4:
+		for _, res := range []*resource{products, users} {
+			if res.hasField(before[1]) {
+				options = append(options, res.name)
+			}
+		}
+	}
+
+	candidates := []string{}
+	seen := map[string]bool{}
+	for _, o := range options {
+		if strings.HasPrefix(o, prefix) && !seen[o] {
+			candidates = append(candidates, o)
+			seen[o] = true
+		}
+	}
+	sort.Strings(candidates)
+	return start, candidates
+}
+
+// commonPrefix - Returns the longest prefix shared by every value
+func commonPrefix(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
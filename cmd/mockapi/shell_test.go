@@ -0,0 +1,126 @@
+// shell_test.go
+
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+	"github.com/carlosrv999/mockapiclient/mockclienttest"
+)
+
+// TestShell tests a scripted session against the fake server
+func TestShell(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+	server.SeedProducts(
+		mockclient.Product{Name: "Ball", Price: 5, Stock: 10, Department: "Toys"},
+		mockclient.Product{Name: "Lamp", Price: 40, Stock: 2, Department: "Home"},
+	)
+
+	script := strings.Join([]string{
+		"get product 1",
+		"set stock 12 on product 1",
+		`set name "Big Ball" on product 1`,
+		"find products department=Toys",
+		"create user name=Alice lastName=Smith",
+		"delete user 1",
+		"get user 1",
+		"frobnicate",
+		"history",
+		"exit",
+		"list products",
+	}, "\n")
+	code, out, _ := runCLI(server.URL, script, "shell")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d", exitOK, code)
+	}
+
+	products := server.Products()
+	if products[0].Stock != 12 || products[0].Name != "Big Ball" {
+		t.Errorf("expected the set commands to update product 1, got %+v", products[0])
+	}
+	if len(server.Users()) != 0 {
+		t.Errorf("expected the created user to be deleted")
+	}
+
+	for _, want := range []string{
+		"\"name\": \"Ball\"",
+		"ID  NAME      PRICE  STOCK",
+		"deleted user 1",
+		"error: user 1 not found",
+		`error: unknown command "frobnicate"`,
+		"   2  set stock 12 on product 1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Lamp") {
+		t.Errorf("expected find to filter and exit to stop the session, got:\n%s", out)
+	}
+}
+
+// TestShell_BadFormat tests that an unknown -o format is rejected before the session starts
+func TestShell_BadFormat(t *testing.T) {
+	server := mockclienttest.NewServer()
+	defer server.Close()
+
+	code, out, errOut := runCLI(server.URL, "list users\n", "-o", "bogus", "shell")
+	if code != exitUsage {
+		t.Errorf("expected exit code %d, got %d", exitUsage, code)
+	}
+	if out != "" || !strings.Contains(errOut, "unknown format") {
+		t.Errorf("expected only a usage error, got %q and %q", out, errOut)
+	}
+}
+
+// TestComplete tests completion of commands, resources and fields
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		line       string
+		start      int
+		candidates []string
+	}{
+		{"", 0, []string{"create", "delete", "exit", "find", "format", "get", "help", "history", "list", "set"}},
+		{"fi", 0, []string{"find"}},
+		{"get p", 4, []string{"product"}},
+		{"find products dep", 14, []string{"department="}},
+		{"find users ", 11, []string{"id=", "name=", "lastName=", "address=", "favoriteDogBreed=", "createdAt="}},
+		{"set st", 4, []string{"stock"}},
+		{"set stock 12 ", 13, []string{"on"}},
+		{"set name x on ", 14, []string{"product", "user"}},
+		{"set stock 12 on ", 16, []string{"product"}},
+		{"get widget ", 11, []string{}},
+	}
+	for _, tt := range tests {
+		start, candidates := complete(tt.line)
+		want := append([]string{}, tt.candidates...)
+		sort.Strings(want)
+		if start != tt.start || !reflect.DeepEqual(candidates, want) {
+			t.Errorf("complete(%q): expected %d %v, got %d %v", tt.line, tt.start, want, start, candidates)
+		}
+	}
+
+	if p := commonPrefix([]string{"format", "find"}); p != "f" {
+		t.Errorf("expected common prefix f, got %q", p)
+	}
+}
+
+// TestSplitWords tests quoting in command lines
+func TestSplitWords(t *testing.T) {
+	words, err := splitWords(`set name "Big  Ball" on product 7`)
+	expected := []string{"set", "name", "Big  Ball", "on", "product", "7"}
+	if err != nil || !reflect.DeepEqual(words, expected) {
+		t.Errorf("expected %q, got %q (%v)", expected, words, err)
+	}
+	if words, _ := splitWords(`set name "" on product 7`); len(words) != 6 || words[2] != "" {
+		t.Errorf("expected an empty quoted word, got %q", words)
+	}
+	if _, err := splitWords(`set name "Big`); err == nil {
+		t.Errorf("expected an unterminated quote error")
+	}
+}
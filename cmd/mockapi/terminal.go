@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInterrupted - Returned by readLine when the user presses Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// lineReader - Reads command lines
+type lineReader interface {
+	readLine(prompt string) (string, error)
+}
+
+// plainReader - Reads lines from non-interactive input, without prompts
+type plainReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plainReader) readLine(prompt string) (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// terminalReader - A minimal line editor for terminals: typing and backspace at the end of the
+// line, Ctrl-U to clear it, tab completion and up/down history. The terminal is switched out of
+// line mode with stty while a line is read, so this needs a Unix-like system.
+type terminalReader struct {
+	in    *bufio.Reader
+	tty   *os.File
+	out   io.Writer
+	shell *shell
+}
+
+// isTerminal - Reports whether f is a terminal that stty can configure
+func isTerminal(f *os.File) bool {
+	_, err := stty(f, "-g")
+	return err == nil
+}
+
+// stty - Runs stty on the terminal f and returns its output
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func (r *terminalReader) readLine(prompt string) (string, error) {
+	saved, err := stty(r.tty, "-g")
+	if err != nil {
+		return "", err
+	}
+	if _, err := stty(r.tty, "raw", "-echo"); err != nil {
+		return "", err
+	}
+	defer stty(r.tty, saved)
+
+	return r.edit(prompt)
+}
+
+// edit - Reads keys until a line is entered, echoing and redrawing it
+func (r *terminalReader) edit(prompt string) (string, error) {
+	line := ""
+	pos := len(r.shell.history) // history index being shown; len means the new line
+	redraw := func() { fmt.Fprintf(r.out, "\r\033[K%s%s", prompt, line) }
+	redraw()
+
+	for {
+		b, err := r.in.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		switch b {
+		case '\r', '\n':
+			fmt.Fprint(r.out, "\r\n")
+			return line, nil
+		case 3: // Ctrl-C
+			fmt.Fprint(r.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if line == "" {
+				fmt.Fprint(r.out, "\r\n")
+				return "", io.EOF
+			}
+		case 21: // Ctrl-U
+			line = ""
+			redraw()
+		case 127, 8: // Backspace
+			if line != "" {
+				_, size := utf8.DecodeLastRuneInString(line)
+				line = line[:len(line)-size]
+				redraw()
+			}
+		case '\t':
+			start, candidates := complete(line)
+			switch {
+			case len(candidates) == 1:
+				line = line[:start] + candidates[0]
+				if !strings.HasSuffix(line, "=") {
+					line += " "
+				}
+			case len(candidates) > 1:
+				line = line[:start] + commonPrefix(candidates)
+				fmt.Fprintf(r.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+			}
+			redraw()
+		case 27: // Escape sequence; only the up and down arrows are handled
+			if next, _ := r.in.ReadByte(); next != '[' {
+				continue
+			}
+			arrow, _ := r.in.ReadByte()
+			history := r.shell.history
+			switch {
+			case arrow == 'A' && pos > 0:
+				pos--
+				line = history[pos]
+			case arrow == 'B' && pos < len(history)-1:
+				pos++
+				line = history[pos]
+			case arrow == 'B' && pos == len(history)-1:
+				pos++
+				line = ""
+			}
+			redraw()
+		default:
+			if b >= 32 {
+				line += string(b)
+				// Multi-byte characters are echoed once all their bytes have arrived
+				if utf8.ValidString(line) {
+					redraw()
+				}
+			}
+		}
+	}
+}
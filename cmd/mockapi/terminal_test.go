@@ -0,0 +1,34 @@
+// terminal_test.go
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestTerminalReader_Edit tests line editing keys against scripted input
+func TestTerminalReader_Edit(t *testing.T) {
+	s := &shell{history: []string{"list users", "get user 1"}}
+	keys := "fi\tproducts sto\t0\r" + // completion
+		"get prx\x7fo\t7\r" + // backspace
+		"\x1b[A\x1b[A\r" + // history
+		"junk\x15list users\r" + // Ctrl-U
+		"half\x03" + // Ctrl-C
+		"\x04" // Ctrl-D
+	r := &terminalReader{in: bufio.NewReader(strings.NewReader(keys)), out: io.Discard, shell: s}
+
+	for _, want := range []string{"find products stock=0", "get product 7", "list users", "list users"} {
+		if line, err := r.edit("> "); err != nil || line != want {
+			t.Errorf("expected %q, got %q (%v)", want, line, err)
+		}
+	}
+	if _, err := r.edit("> "); err != errInterrupted {
+		t.Errorf("expected Ctrl-C to interrupt, got %v", err)
+	}
+	if _, err := r.edit("> "); err != io.EOF {
+		t.Errorf("expected Ctrl-D to end input, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package mockclient
+
+import (
+	"net/http"
+	"sync"
+)
+
+// flightGroup - Deduplicates concurrent calls with the same key so only one runs at a time
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall - An in-flight or completed call shared by all callers with the same key
+type flightCall struct {
+	wg     sync.WaitGroup
+	body   []byte
+	header http.Header
+	err    error
+}
+
+// do - Runs fn once for all concurrent callers of key; each caller receives its own copy of the result
+func (g *flightGroup) do(key string, fn func() ([]byte, http.Header, error)) ([]byte, http.Header, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.result()
+	}
+
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	func() {
+		defer func() {
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			call.wg.Done()
+		}()
+		call.body, call.header, call.err = fn()
+	}()
+
+	return call.result()
+}
+
+// result - Returns a private copy of the call's result
+func (call *flightCall) result() ([]byte, http.Header, error) {
+	if call.err != nil {
+		return nil, nil, call.err
+	}
+
+	return append([]byte(nil), call.body...), call.header.Clone(), nil
+}
@@ -0,0 +1,76 @@
+// coalesce_test.go
+
+package mockclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCoalesceReads tests that concurrent identical GETs share a single request
+func TestCoalesceReads(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	// Create a mock server that blocks until all callers are waiting
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "name": "Product 1", "price": "10.00", "stock": "50"}]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.CoalesceReads = true
+
+	const callers = 50
+	var started, done sync.WaitGroup
+	results := make([][]Product, callers)
+	errs := make([]error, callers)
+	started.Add(callers)
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			results[i], errs[i] = client.GetProducts()
+		}(i)
+	}
+	started.Wait()
+	// Give the remaining callers time to join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if n := atomic.LoadInt32(&requests); n >= callers {
+		t.Errorf("expected requests to be coalesced, got %d requests for %d callers", n, callers)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: expected no error, got %v", i, errs[i])
+		}
+	}
+
+	results[0][0].Name = "Mutated"
+	if results[1][0].Name != "Product 1" {
+		t.Errorf("expected callers to receive independent results, got %s", results[1][0].Name)
+	}
+}
+
+// TestFlightGroup tests that waiting callers receive copies of the shared body
+func TestFlightGroup(t *testing.T) {
+	var g flightGroup
+	body, _, err := g.do("key", func() ([]byte, http.Header, error) {
+		return []byte("shared"), http.Header{}, nil
+	})
+	if err != nil || string(body) != "shared" {
+		t.Fatalf("expected body 'shared' and no error, got %q and %v", body, err)
+	}
+	if len(g.calls) != 0 {
+		t.Errorf("expected completed calls to be forgotten, got %d", len(g.calls))
+	}
+}
@@ -0,0 +1,140 @@
+package mockclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrOffline is returned for writes, and for reads with no cached response, while the client is offline
+var ErrOffline = errors.New("client is offline")
+
+// DiskCache - A directory of JSON files holding GET responses and their metadata
+type DiskCache struct {
+	Dir string
+	now func() time.Time
+}
+
+// CachedResponseInfo - Describes a response served from the disk cache
+type CachedResponseInfo struct {
+	URL       string
+	FetchedAt time.Time
+	Age       time.Duration
+}
+
+// diskEntry - The on-disk representation of a cached response
+type diskEntry struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Header    http.Header     `json:"header,omitempty"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// NewDiskCache - Creates a disk cache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &DiskCache{Dir: dir, now: time.Now}, nil
+}
+
+// Info - Returns metadata about the cached response for url, if any
+func (dc *DiskCache) Info(url string) (CachedResponseInfo, bool) {
+	entry, err := dc.load(url)
+	if err != nil {
+		return CachedResponseInfo{}, false
+	}
+
+	return dc.info(entry), true
+}
+
+// offlineResponse - Serves a GET from the disk cache, refusing every other request
+func (c *Client) offlineResponse(req *http.Request) ([]byte, http.Header, error) {
+	if req.Method != http.MethodGet {
+		return nil, nil, fmt.Errorf("%w: cannot %s %s", ErrOffline, req.Method, req.URL.Path)
+	}
+	if c.DiskCache == nil {
+		return nil, nil, fmt.Errorf("%w: no disk cache configured", ErrOffline)
+	}
+
+	entry, err := c.DiskCache.load(req.URL.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: no cached response for %s", ErrOffline, req.URL.Path)
+	}
+
+	if c.OnCachedRead != nil {
+		c.OnCachedRead(c.DiskCache.info(entry))
+	}
+
+	return []byte(entry.Body), entry.Header, nil
+}
+
+// store - Writes a response to disk; bodies that are not JSON are not cached
+func (dc *DiskCache) store(url string, body []byte, header http.Header) error {
+	if !json.Valid(body) {
+		return fmt.Errorf("response for %s is not JSON", url)
+	}
+
+	data, err := json.MarshalIndent(diskEntry{URL: url, FetchedAt: dc.clock().UTC(), Header: header, Body: body}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(dc.Dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dc.path(url))
+}
+
+// load - Reads the cached response for url
+func (dc *DiskCache) load(url string) (*diskEntry, error) {
+	data, err := os.ReadFile(dc.path(url))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &diskEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// info - Builds the staleness report for an entry
+func (dc *DiskCache) info(entry *diskEntry) CachedResponseInfo {
+	return CachedResponseInfo{URL: entry.URL, FetchedAt: entry.FetchedAt, Age: dc.clock().Sub(entry.FetchedAt)}
+}
+
+// clock - Returns the current time
+func (dc *DiskCache) clock() time.Time {
+	if dc.now == nil {
+		return time.Now()
+	}
+	return dc.now()
+}
+
+// path - File holding the cached response for url
+func (dc *DiskCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dc.Dir, hex.EncodeToString(sum[:16])+".json")
+}
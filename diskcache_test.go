@@ -0,0 +1,78 @@
+// diskcache_test.go
+
+package mockclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDiskCache_OfflineReads tests that responses fetched online are served offline with their age
+func TestDiskCache_OfflineReads(t *testing.T) {
+	// Create a mock server that is only used while online
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "name": "John", "lastName": "Doe"}]`))
+	}))
+
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("expected no error creating cache, got %v", err)
+	}
+	fetchedAt := time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return fetchedAt }
+
+	client, _ := NewClient(&server.URL)
+	client.DiskCache = cache
+	if _, err := client.GetUsers(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	server.Close()
+
+	cache.now = func() time.Time { return fetchedAt.Add(2 * time.Hour) }
+	var info CachedResponseInfo
+	client.Offline = true
+	client.OnCachedRead = func(i CachedResponseInfo) { info = i }
+
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatalf("expected no error offline, got %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "John" {
+		t.Errorf("expected cached user John, got %+v", users)
+	}
+	if info.Age != 2*time.Hour || !info.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("expected cached result aged 2h fetched at %v, got %+v", fetchedAt, info)
+	}
+}
+
+// TestDiskCache_OfflineMiss tests an offline read with nothing cached
+func TestDiskCache_OfflineMiss(t *testing.T) {
+	cache, _ := NewDiskCache(t.TempDir())
+	hostURL := "http://example.invalid"
+	client, _ := NewClient(&hostURL)
+	client.DiskCache = cache
+	client.Offline = true
+
+	_, err := client.GetProductByID(1)
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+}
+
+// TestOffline_Writes tests that writes fail while offline
+func TestOffline_Writes(t *testing.T) {
+	hostURL := "http://example.invalid"
+	client, _ := NewClient(&hostURL)
+	client.Offline = true
+
+	if _, err := client.CreateUser(&User{Name: "Alice"}); !errors.Is(err, ErrOffline) {
+		t.Errorf("expected ErrOffline from CreateUser, got %v", err)
+	}
+	if err := client.DeleteProduct(1); !errors.Is(err, ErrOffline) {
+		t.Errorf("expected ErrOffline from DeleteProduct, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package mockclient
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// Snapshot - A stored copy of the users and products on the server
+type Snapshot struct {
+	TakenAt  time.Time `json:"takenAt"`
+	Users    []User    `json:"users"`
+	Products []Product `json:"products"`
+}
+
+// DriftItem - A record that was added, removed or changed since a snapshot
+type DriftItem struct {
+	Resource string                 `json:"resource"`
+	ID       int                    `json:"id"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Changes  []FieldChange          `json:"changes,omitempty"`
+}
+
+// DriftReport - The differences between a snapshot and the live server
+type DriftReport struct {
+	CheckedAt time.Time   `json:"checkedAt"`
+	Added     []DriftItem `json:"added"`
+	Removed   []DriftItem `json:"removed"`
+	Changed   []DriftItem `json:"changed"`
+}
+
+// TakeSnapshot - Reads all users and products from the server
+func (c *Client) TakeSnapshot() (*Snapshot, error) {
+	users, err := c.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	products, err := c.GetProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{TakenAt: time.Now().UTC(), Users: users, Products: products}, nil
+}
+
+// LoadSnapshot - Reads a snapshot previously written by Save
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Save - Writes the snapshot to path as JSON
+func (s *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// DetectDrift - Compares a snapshot with the live server by ID without modifying anything.
+// Server-managed createdAt values are not compared.
+func (c *Client) DetectDrift(snapshot *Snapshot) (*DriftReport, error) {
+	live, err := c.TakeSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{CheckedAt: live.TakenAt, Added: []DriftItem{}, Removed: []DriftItem{}, Changed: []DriftItem{}}
+
+	oldUsers := map[int]interface{}{}
+	for _, u := range snapshot.Users {
+		oldUsers[u.ID] = u
+	}
+	newUsers := map[int]interface{}{}
+	for _, u := range live.Users {
+		newUsers[u.ID] = u
+	}
+	if err := report.compare("user", oldUsers, newUsers); err != nil {
+		return nil, err
+	}
+
+	oldProducts := map[int]interface{}{}
+	for _, p := range snapshot.Products {
+		oldProducts[p.ID] = p
+	}
+	newProducts := map[int]interface{}{}
+	for _, p := range live.Products {
+		newProducts[p.ID] = p
+	}
+	if err := report.compare("product", oldProducts, newProducts); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// HasDrift - Reports whether anything differs
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// WriteJSON - Writes the report to w as indented JSON
+func (r *DriftReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// compare - Appends the drift between two sets of records, keyed by ID, in ascending ID order
+func (r *DriftReport) compare(resource string, before, after map[int]interface{}) error {
+	ids := map[int]bool{}
+	for id := range before {
+		ids[id] = true
+	}
+	for id := range after {
+		ids[id] = true
+	}
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Ints(sorted)
+
+	for _, id := range sorted {
+		oldRecord, hadOld := before[id]
+		newRecord, hasNew := after[id]
+
+		switch {
+		case !hadOld:
+			fields, err := recordFields(newRecord)
+			if err != nil {
+				return err
+			}
+			r.Added = append(r.Added, DriftItem{Resource: resource, ID: id, Fields: fields})
+		case !hasNew:
+			fields, err := recordFields(oldRecord)
+			if err != nil {
+				return err
+			}
+			r.Removed = append(r.Removed, DriftItem{Resource: resource, ID: id, Fields: fields})
+		default:
+			have, err := recordFields(oldRecord)
+			if err != nil {
+				return err
+			}
+			want, err := recordFields(newRecord)
+			if err != nil {
+				return err
+			}
+			names := map[string]interface{}{}
+			for k := range have {
+				names[k] = nil
+			}
+			for k := range want {
+				names[k] = nil
+			}
+			if changes := compareFields(have, want, sortedKeys(names)); len(changes) > 0 {
+				r.Changed = append(r.Changed, DriftItem{Resource: resource, ID: id, Changes: changes})
+			}
+		}
+	}
+
+	return nil
+}
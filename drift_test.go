@@ -0,0 +1,94 @@
+// drift_test.go
+
+package mockclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDetectDrift tests drift detection for added, removed and changed records
+func TestDetectDrift(t *testing.T) {
+	// Create a mock server whose data differs from the snapshot
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`[{"id": "1", "name": "John", "lastName": "Doe", "favoriteDogBreed": "Poodle"}, {"id": "3", "name": "Alice", "lastName": "Brown"}]`))
+		case "/products":
+			w.Write([]byte(`[{"id": "5", "name": "Ball", "price": "5.00", "stock": "10"}]`))
+		}
+	}))
+	defer server.Close()
+
+	snapshot := &Snapshot{
+		Users: []User{
+			{ID: 1, Name: "John", LastName: "Doe", FavoriteDogBreed: "Beagle"},
+			{ID: 2, Name: "Jane", LastName: "Smith"},
+		},
+		Products: []Product{{ID: 5, Name: "Ball", Price: 5, Stock: 10}},
+	}
+
+	client, _ := NewClient(&server.URL)
+	report, err := client.DetectDrift(snapshot)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !report.HasDrift() {
+		t.Fatalf("expected drift to be detected")
+	}
+	if len(report.Added) != 1 || report.Added[0].ID != 3 || report.Added[0].Fields["name"] != "Alice" {
+		t.Errorf("unexpected added records: %+v", report.Added)
+	}
+	if len(report.Removed) != 1 || report.Removed[0].ID != 2 {
+		t.Errorf("unexpected removed records: %+v", report.Removed)
+	}
+	if len(report.Changed) != 1 {
+		t.Fatalf("expected 1 changed record, got %+v", report.Changed)
+	}
+	change := report.Changed[0].Changes
+	if len(change) != 1 || change[0].Field != "favoriteDogBreed" || change[0].Old != "Beagle" || change[0].New != "Poodle" {
+		t.Errorf("unexpected changes: %+v", change)
+	}
+
+	var out strings.Builder
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatalf("expected no error writing report, got %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Errorf("expected report to be valid JSON, got %v", err)
+	}
+}
+
+// TestSnapshotSaveLoad tests round-tripping a snapshot through a file
+func TestSnapshotSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	snapshot := &Snapshot{
+		Users:    []User{{ID: 1, Name: "John"}},
+		Products: []Product{{ID: 2, Name: "Ball", Price: 1.5, Stock: 3}},
+	}
+
+	if err := snapshot.Save(path); err != nil {
+		t.Fatalf("expected no error saving snapshot, got %v", err)
+	}
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("expected no error loading snapshot, got %v", err)
+	}
+
+	if len(loaded.Users) != 1 || loaded.Users[0] != snapshot.Users[0] {
+		t.Errorf("expected users %+v, got %+v", snapshot.Users, loaded.Users)
+	}
+	if len(loaded.Products) != 1 || loaded.Products[0] != snapshot.Products[0] {
+		t.Errorf("expected products %+v, got %+v", snapshot.Products, loaded.Products)
+	}
+}
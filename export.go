@@ -0,0 +1,369 @@
+package mockclient
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// DefaultExportPageSize is the page size used when ExportOptions.PageSize is zero
+const DefaultExportPageSize = 100
+
+// ErrCheckpointMismatch is returned when resuming from a checkpoint taken by a different export
+var ErrCheckpointMismatch = errors.New("checkpoint does not match export")
+
+// ExportOptions - Controls ExportUsers and ExportProducts
+type ExportOptions struct {
+	// Format is FormatCSV, FormatNDJSON or FormatJSON; defaults to FormatNDJSON
+	Format Format
+	// Columns selects and orders the fields to write by JSON name; empty means every field
+	Columns []string
+	// UserFilter and ProductFilter, when set, skip records for which they return false
+	UserFilter    func(User) bool
+	ProductFilter func(Product) bool
+	// FilterKey identifies the filter, so that a checkpoint is only resumed with the same one;
+	// set it whenever a filter is set, e.g. to the conditions the filter checks
+	FilterKey string
+	// PageSize is the number of records requested per page; defaults to DefaultExportPageSize
+	PageSize int
+	// Resume continues an export from a checkpoint; w must already hold the first Checkpoint.Bytes bytes
+	Resume *ExportCheckpoint
+	// Checkpoint, when set, is called after each page has been written
+	Checkpoint func(ExportCheckpoint) error
+}
+
+// ExportCheckpoint - How far an export got; pass it as ExportOptions.Resume to continue
+type ExportCheckpoint struct {
+	Resource string   `json:"resource"`
+	Format   Format   `json:"format"`
+	Columns  []string `json:"columns"`
+	Filter   string   `json:"filter,omitempty"`
+	PageSize int      `json:"pageSize"`
+	NextPage int      `json:"nextPage"`
+	Records  int      `json:"records"`
+	Bytes    int64    `json:"bytes"`
+	Done     bool     `json:"done"`
+}
+
+// ExportUsers - Streams users to w one page at a time, so at most one page is held in memory.
+// Pages are requested with mockapi.io's page and limit query parameters; a server that ignores them
+// returns the whole collection at once, which is detected and written as a single page. Records
+// created or deleted during an export can shift page boundaries.
+func (c *Client) ExportUsers(w io.Writer, opts ExportOptions) (*ExportCheckpoint, error) {
+	return exportPages(c, w, "user", "user", opts, opts.UserFilter)
+}
+
+// ExportProducts - Streams products to w one page at a time; see ExportUsers
+func (c *Client) ExportProducts(w io.Writer, opts ExportOptions) (*ExportCheckpoint, error) {
+	return exportPages(c, w, "product", "products", opts, opts.ProductFilter)
+}
+
+// ExportUsersToFile - Exports users to path, resuming from path+".checkpoint" when an earlier export
+// to the same file failed. The checkpoint is removed once the export completes.
+func (c *Client) ExportUsersToFile(path string, opts ExportOptions) (*ExportCheckpoint, error) {
+	return exportToFile(path, opts, c.ExportUsers)
+}
+
+// ExportProductsToFile - Exports products to path, resuming from path+".checkpoint"; see ExportUsersToFile
+func (c *Client) ExportProductsToFile(path string, opts ExportOptions) (*ExportCheckpoint, error) {
+	return exportToFile(path, opts, c.ExportProducts)
+}
+
+// MatchFields - Reports whether every named field of record, by JSON name, has the given value
+// as written in table and CSV output (e.g. "stock" = "0", "price" = "12.5")
+func MatchFields(record interface{}, conditions map[string]string) (bool, error) {
+	v := reflect.Indirect(reflect.ValueOf(record))
+	if v.Kind() != reflect.Struct {
+		return false, fmt.Errorf("cannot match %T", record)
+	}
+	all := columnsOf(v.Type())
+	match := true
+	for name, want := range conditions {
+		c, ok := findColumn(all, name)
+		if !ok {
+			return false, fmt.Errorf("%w %q", ErrUnknownColumn, name)
+		}
+		if cellText(v.Field(c.index)) != want {
+			match = false
+		}
+	}
+	return match, nil
+}
+
+// exportPages - Fetches pages of T from path and writes the records that pass filter
+func exportPages[T any](c *Client, w io.Writer, resource, path string, opts ExportOptions, filter func(T) bool) (*ExportCheckpoint, error) {
+	if opts.Format == "" {
+		opts.Format = FormatNDJSON
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultExportPageSize
+	}
+
+	var zero T
+	cols, err := selectColumns(columnsOf(reflect.TypeOf(zero)), opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+
+	cp := ExportCheckpoint{Resource: resource, Format: opts.Format, Columns: names, Filter: opts.FilterKey, PageSize: opts.PageSize, NextPage: 1}
+	if opts.Resume != nil {
+		r := *opts.Resume
+		if r.Resource != cp.Resource || r.Format != cp.Format || r.PageSize != cp.PageSize {
+			return nil, fmt.Errorf("%w: %s %s with page size %d", ErrCheckpointMismatch, r.Format, r.Resource, r.PageSize)
+		}
+		if strings.Join(r.Columns, ",") != strings.Join(cp.Columns, ",") {
+			return nil, fmt.Errorf("%w: columns %s", ErrCheckpointMismatch, strings.Join(r.Columns, ","))
+		}
+		if r.Filter != cp.Filter {
+			return nil, fmt.Errorf("%w: filter %q", ErrCheckpointMismatch, r.Filter)
+		}
+		cp = r
+	}
+	if cp.Done {
+		return &cp, nil
+	}
+
+	switch opts.Format {
+	case FormatCSV, FormatNDJSON, FormatJSON:
+	default:
+		return nil, fmt.Errorf("%w %q for export", ErrUnknownFormat, opts.Format)
+	}
+
+	out := &exportWriter{w: w, format: opts.Format, cols: cols, records: cp.Records}
+	if opts.Resume == nil {
+		if err := out.begin(); err != nil {
+			return nil, err
+		}
+	}
+
+	var previousFirst *T
+	for !cp.Done {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s?page=%d&limit=%d", c.HostURL, path, cp.NextPage, cp.PageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := c.doRequest(req)
+		if err != nil {
+			return &cp, fmt.Errorf("page %d: %w", cp.NextPage, err)
+		}
+		page := []T{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return &cp, fmt.Errorf("page %d: %w", cp.NextPage, err)
+		}
+
+		// A server that ignores the paging parameters answers every page with the same records
+		if len(page) > 0 && previousFirst != nil && reflect.DeepEqual(page[0], *previousFirst) {
+			page = page[:0]
+		}
+		if len(page) > 0 {
+			previousFirst = &page[0]
+		}
+
+		for _, record := range page {
+			if filter != nil && !filter(record) {
+				continue
+			}
+			if err := out.write(reflect.ValueOf(record)); err != nil {
+				return &cp, err
+			}
+		}
+
+		// A short page is the last one; a page larger than requested means paging is unsupported
+		// and the whole collection has just been written
+		if len(page) != cp.PageSize {
+			if err := out.end(); err != nil {
+				return &cp, err
+			}
+			cp.Done = true
+		}
+		if err := out.flush(); err != nil {
+			return &cp, err
+		}
+
+		cp.NextPage++
+		cp.Records = out.records
+		cp.Bytes += out.written
+		out.written = 0
+		if opts.Checkpoint != nil {
+			if err := opts.Checkpoint(cp); err != nil {
+				return &cp, err
+			}
+		}
+	}
+
+	return &cp, nil
+}
+
+// exportWriter - Writes records in an export format, counting the bytes written
+type exportWriter struct {
+	w       io.Writer
+	format  Format
+	cols    []column
+	csv     *csv.Writer
+	records int
+	written int64
+}
+
+// Write - Counts bytes on their way to the underlying writer
+func (e *exportWriter) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	e.written += int64(n)
+	return n, err
+}
+
+// begin - Writes what precedes the first record: the CSV header or the opening bracket
+func (e *exportWriter) begin() error {
+	switch e.format {
+	case FormatCSV:
+		header := make([]string, len(e.cols))
+		for i, c := range e.cols {
+			header[i] = c.name
+		}
+		return e.csvWriter().Write(header)
+	case FormatJSON:
+		_, err := io.WriteString(e, "[")
+		return err
+	}
+	return nil
+}
+
+// write - Writes one record
+func (e *exportWriter) write(row reflect.Value) error {
+	e.records++
+	switch e.format {
+	case FormatCSV:
+		cells := make([]string, len(e.cols))
+		for i, c := range e.cols {
+			cells[i] = cellText(row.Field(c.index))
+		}
+		return e.csvWriter().Write(cells)
+	case FormatJSON:
+		obj, err := wireObject(row, e.cols)
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if e.records == 1 {
+			sep = "\n  "
+		}
+		_, err = io.WriteString(e, sep+string(obj))
+		return err
+	}
+	obj, err := wireObject(row, e.cols)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e, string(obj)+"\n")
+	return err
+}
+
+// end - Writes what follows the last record
+func (e *exportWriter) end() error {
+	if e.format != FormatJSON {
+		return nil
+	}
+	if e.records == 0 {
+		_, err := io.WriteString(e, "]\n")
+		return err
+	}
+	_, err := io.WriteString(e, "\n]\n")
+	return err
+}
+
+// flush - Pushes buffered CSV output through, so the byte count is exact at page boundaries
+func (e *exportWriter) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// csvWriter - Returns the CSV writer, creating it on first use
+func (e *exportWriter) csvWriter() *csv.Writer {
+	if e.csv == nil {
+		e.csv = csv.NewWriter(e)
+	}
+	return e.csv
+}
+
+// exportToFile - Runs export into path, saving a checkpoint after every page and resuming from one
+func exportToFile(path string, opts ExportOptions, export func(io.Writer, ExportOptions) (*ExportCheckpoint, error)) (*ExportCheckpoint, error) {
+	cpPath := path + ".checkpoint"
+
+	var resume *ExportCheckpoint
+	if data, err := os.ReadFile(cpPath); err == nil {
+		resume = &ExportCheckpoint{}
+		if err := json.Unmarshal(data, resume); err != nil {
+			return nil, fmt.Errorf("%s: %w", cpPath, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Drop anything written after the last checkpoint, or everything when starting over
+	var offset int64
+	if resume != nil {
+		offset = resume.Bytes
+	}
+	if err := f.Truncate(offset); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	opts.Resume = resume
+	next := opts.Checkpoint
+	opts.Checkpoint = func(cp ExportCheckpoint) error {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		if err := saveCheckpoint(cpPath, cp); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(cp)
+		}
+		return nil
+	}
+
+	cp, err := export(f, opts)
+	if err != nil {
+		return cp, err
+	}
+	if err := os.Remove(cpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cp, err
+	}
+	return cp, nil
+}
+
+// saveCheckpoint - Atomically replaces the checkpoint file
+func saveCheckpoint(path string, cp ExportCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,182 @@
+// export_test.go
+
+package mockclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// newPagingServer - Serves n products with mockapi.io paging, failing the pages listed in fail once each
+func newPagingServer(t *testing.T, n int, fail map[int]bool, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RequestURI())
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if fail[page] {
+			delete(fail, page)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		records := []Product{}
+		for id := (page-1)*limit + 1; id <= page*limit && id <= n; id++ {
+			records = append(records, Product{ID: id, Name: fmt.Sprintf("Product %d", id), Price: float64(id), Stock: id % 2})
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(records)
+	}))
+}
+
+// TestExportProducts_CSV tests paging, filtering and projection
+func TestExportProducts_CSV(t *testing.T) {
+	var requests []string
+	server := newPagingServer(t, 5, nil, &requests)
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	var buf bytes.Buffer
+	var checkpoints []ExportCheckpoint
+	cp, err := client.ExportProducts(&buf, ExportOptions{
+		Format:        FormatCSV,
+		Columns:       []string{"id", "name"},
+		PageSize:      2,
+		ProductFilter: func(p Product) bool { return p.Stock > 0 },
+		Checkpoint:    func(cp ExportCheckpoint) error { checkpoints = append(checkpoints, cp); return nil },
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "id,name\n1,Product 1\n3,Product 3\n5,Product 5\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+	if len(requests) != 3 || requests[2] != "/products?page=3&limit=2" {
+		t.Errorf("expected three page requests, got %v", requests)
+	}
+	if len(checkpoints) != 3 || !cp.Done || cp.Records != 3 || cp.Bytes != int64(len(expected)) {
+		t.Errorf("expected a checkpoint per page ending done, got %+v", checkpoints)
+	}
+}
+
+// TestExportProductsToFile_Resume tests that a failed export continues where it stopped
+func TestExportProductsToFile_Resume(t *testing.T) {
+	var requests []string
+	server := newPagingServer(t, 5, map[int]bool{2: true}, &requests)
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	path := filepath.Join(t.TempDir(), "products.json")
+	opts := ExportOptions{Format: FormatJSON, Columns: []string{"id"}, PageSize: 2}
+
+	if _, err := client.ExportProductsToFile(path, opts); err == nil {
+		t.Fatalf("expected the first export to fail on page 2")
+	}
+	if _, err := os.Stat(path + ".checkpoint"); err != nil {
+		t.Fatalf("expected a checkpoint after the failure, got %v", err)
+	}
+
+	cp, err := client.ExportProductsToFile(path, opts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cp.Records != 5 {
+		t.Errorf("expected 5 records, got %d", cp.Records)
+	}
+	if requests[2] != "/products?page=2&limit=2" {
+		t.Errorf("expected the resumed export to start at page 2, got %v", requests)
+	}
+
+	data, _ := os.ReadFile(path)
+	var ids []map[string]string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		t.Fatalf("expected a valid JSON array, got %v:\n%s", err, data)
+	}
+	if len(ids) != 5 || ids[4]["id"] != "5" {
+		t.Errorf("expected each product exactly once, got %v", ids)
+	}
+	if _, err := os.Stat(path + ".checkpoint"); !os.IsNotExist(err) {
+		t.Errorf("expected the checkpoint to be removed, got %v", err)
+	}
+}
+
+// TestExportProductsToFile_ResumeMismatch tests that a checkpoint is not resumed with other columns or another filter
+func TestExportProductsToFile_ResumeMismatch(t *testing.T) {
+	var requests []string
+	server := newPagingServer(t, 5, map[int]bool{2: true}, &requests)
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	path := filepath.Join(t.TempDir(), "products.csv")
+	inStock := func(p Product) bool { return p.Stock > 0 }
+	opts := ExportOptions{Format: FormatCSV, Columns: []string{"id", "name"}, PageSize: 2, ProductFilter: inStock, FilterKey: "in stock"}
+
+	if _, err := client.ExportProductsToFile(path, opts); err == nil {
+		t.Fatalf("expected the first export to fail on page 2")
+	}
+	before, _ := os.ReadFile(path)
+
+	columns := opts
+	columns.Columns = []string{"id"}
+	filter := opts
+	filter.FilterKey = "all"
+	filter.ProductFilter = nil
+	for _, o := range []ExportOptions{columns, filter} {
+		if _, err := client.ExportProductsToFile(path, o); !errors.Is(err, ErrCheckpointMismatch) {
+			t.Errorf("expected ErrCheckpointMismatch, got %v", err)
+		}
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(before, after) {
+		t.Errorf("expected the file to be left alone, got %q", after)
+	}
+
+	if _, err := client.ExportProductsToFile(path, opts); err != nil {
+		t.Fatalf("expected the original export to resume, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "id,name\n1,Product 1\n3,Product 3\n5,Product 5\n" {
+		t.Errorf("expected the products in stock, got %q", data)
+	}
+}
+
+// TestExportUsers_NoPaging tests a server that ignores the paging parameters
+func TestExportUsers_NoPaging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "name": "Alice"}, {"id": "2", "name": "Bob"}]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	for _, size := range []int{1, 2} {
+		var buf bytes.Buffer
+		if _, err := client.ExportUsers(&buf, ExportOptions{PageSize: size, Columns: []string{"name"}}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if expected := "{\"name\":\"Alice\"}\n{\"name\":\"Bob\"}\n"; buf.String() != expected {
+			t.Errorf("page size %d: expected %q, got %q", size, expected, buf.String())
+		}
+	}
+}
+
+// TestMatchFields tests matching records by JSON field values
+func TestMatchFields(t *testing.T) {
+	product := Product{Name: "Kite", Price: 12.5, Department: "Toys"}
+	if ok, err := MatchFields(product, map[string]string{"department": "Toys", "price": "12.5"}); !ok || err != nil {
+		t.Errorf("expected a match, got %v %v", ok, err)
+	}
+	if ok, _ := MatchFields(&product, map[string]string{"stock": "1"}); ok {
+		t.Errorf("expected no match")
+	}
+	if _, err := MatchFields(product, map[string]string{"color": "red"}); err == nil {
+		t.Errorf("expected an unknown column error")
+	}
+}
@@ -0,0 +1,156 @@
+package mockclient
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+var (
+	firstNames = []string{
+		"Olivia", "Liam", "Emma", "Noah", "Ava", "Oliver", "Sophia", "Elijah", "Isabella", "Lucas",
+		"Mia", "Mateo", "Amelia", "Ethan", "Harper", "James", "Evelyn", "Benjamin", "Camila", "Daniel",
+		"Sofia", "Carlos", "Lucia", "Diego", "Valentina", "Hiroshi", "Aiko", "Priya", "Arjun", "Fatima",
+	}
+	lastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+		"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+		"Lee", "Perez", "Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Nakamura", "Patel",
+	}
+	streetNames = []string{
+		"Main", "Oak", "Pine", "Maple", "Cedar", "Elm", "Washington", "Lake", "Hill", "Park",
+		"Sunset", "Lincoln", "Church", "River", "Highland", "Meadow", "Forest", "Spring", "Willow", "Jackson",
+	}
+	streetSuffixes = []string{"St", "Ave", "Blvd", "Rd", "Ln", "Dr", "Ct", "Way"}
+	cities         = []string{
+		"Springfield", "Riverside", "Fairview", "Madison", "Georgetown", "Franklin", "Clinton", "Salem",
+		"Arlington", "Ashland", "Burlington", "Dayton", "Lima", "Cusco", "Austin", "Portland",
+	}
+	dogBreeds = []string{
+		"Labrador Retriever", "German Shepherd", "Golden Retriever", "French Bulldog", "Bulldog", "Poodle",
+		"Beagle", "Rottweiler", "Dachshund", "German Shorthaired Pointer", "Pembroke Welsh Corgi",
+		"Australian Shepherd", "Yorkshire Terrier", "Boxer", "Cavalier King Charles Spaniel", "Doberman Pinscher",
+		"Great Dane", "Miniature Schnauzer", "Siberian Husky", "Bernese Mountain Dog", "Shih Tzu",
+		"Boston Terrier", "Pomeranian", "Havanese", "Shetland Sheepdog", "Border Collie", "Chihuahua",
+		"Basset Hound", "Peruvian Inca Orchid", "Shiba Inu",
+	}
+	productAdjectives = []string{
+		"Ergonomic", "Rustic", "Sleek", "Handcrafted", "Refined", "Practical", "Gorgeous", "Compact",
+		"Durable", "Lightweight", "Premium", "Classic",
+	}
+	productMaterials = []string{"Wooden", "Steel", "Cotton", "Plastic", "Granite", "Bamboo", "Leather", "Ceramic", "Rubber", "Glass"}
+)
+
+// departmentCatalog - Product types sold by a department and its typical (median) price
+type departmentCatalog struct {
+	name   string
+	types  []string
+	median float64
+}
+
+var departments = []departmentCatalog{
+	{"Electronics", []string{"Headphones", "Keyboard", "Monitor", "Speaker", "Charger", "Camera"}, 120},
+	{"Toys", []string{"Puzzle", "Ball", "Kite", "Doll", "Robot", "Board Game"}, 20},
+	{"Home", []string{"Lamp", "Chair", "Table", "Rug", "Pillow", "Clock"}, 60},
+	{"Garden", []string{"Shovel", "Hose", "Planter", "Rake", "Sprinkler"}, 35},
+	{"Sports", []string{"Bike", "Racket", "Gloves", "Helmet", "Yoga Mat"}, 45},
+	{"Clothing", []string{"Shirt", "Jacket", "Shoes", "Hat", "Scarf", "Socks"}, 30},
+	{"Grocery", []string{"Coffee", "Tea", "Chocolate", "Olive Oil", "Pasta"}, 8},
+	{"Books", []string{"Novel", "Cookbook", "Atlas", "Notebook"}, 15},
+}
+
+// Generator - Produces plausible users and products; the same seed always yields the same sequence
+type Generator struct {
+	rng *rand.Rand
+}
+
+// NewGenerator - Creates a generator seeded with seed
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// User - Returns a new user with no ID
+func (g *Generator) User() User {
+	return User{
+		Name:             g.pick(firstNames),
+		LastName:         g.pick(lastNames),
+		Address:          fmt.Sprintf("%d %s %s, %s", 1+g.rng.Intn(9999), g.pick(streetNames), g.pick(streetSuffixes), g.pick(cities)),
+		FavoriteDogBreed: g.pick(dogBreeds),
+	}
+}
+
+// Product - Returns a new product with no ID.
+// Prices are log-normally distributed around the department median; about one in ten products is out of stock.
+func (g *Generator) Product() Product {
+	dept := departments[g.rng.Intn(len(departments))]
+	productType := g.pick(dept.types)
+
+	price := math.Round(dept.median*math.Exp(g.rng.NormFloat64()*0.5)*100) / 100
+	if price < 0.99 {
+		price = 0.99
+	}
+
+	stock := 0
+	if g.rng.Float64() >= 0.1 {
+		stock = 1 + int(g.rng.ExpFloat64()*40)
+	}
+
+	return Product{
+		Name:       fmt.Sprintf("%s %s %s", g.pick(productAdjectives), g.pick(productMaterials), productType),
+		Price:      price,
+		Stock:      stock,
+		Type:       productType,
+		Department: dept.name,
+	}
+}
+
+// Users - Returns n new users
+func (g *Generator) Users(n int) []User {
+	users := make([]User, n)
+	for i := range users {
+		users[i] = g.User()
+	}
+	return users
+}
+
+// Products - Returns n new products
+func (g *Generator) Products(n int) []Product {
+	products := make([]Product, n)
+	for i := range products {
+		products[i] = g.Product()
+	}
+	return products
+}
+
+// pick - Returns a random element of values
+func (g *Generator) pick(values []string) string {
+	return values[g.rng.Intn(len(values))]
+}
+
+// PopulateUsers - Creates n generated users, returning the ones created before any error
+func (c *Client) PopulateUsers(gen *Generator, n int) ([]User, error) {
+	created := []User{}
+	for i := 0; i < n; i++ {
+		user := gen.User()
+		newUser, err := c.CreateUser(&user)
+		if err != nil {
+			return created, fmt.Errorf("user %d of %d: %w", i+1, n, err)
+		}
+		created = append(created, *newUser)
+	}
+	return created, nil
+}
+
+// PopulateProducts - Creates n generated products, returning the ones created before any error
+func (c *Client) PopulateProducts(gen *Generator, n int) ([]Product, error) {
+	created := []Product{}
+	for i := 0; i < n; i++ {
+		product := gen.Product()
+		newProduct, err := c.CreateProduct(&product)
+		if err != nil {
+			return created, fmt.Errorf("product %d of %d: %w", i+1, n, err)
+		}
+		created = append(created, *newProduct)
+	}
+	return created, nil
+}
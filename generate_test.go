@@ -0,0 +1,80 @@
+// generate_test.go
+
+package mockclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGenerator_Deterministic tests that the same seed produces the same records
+func TestGenerator_Deterministic(t *testing.T) {
+	a, b := NewGenerator(7), NewGenerator(7)
+	for i := 0; i < 20; i++ {
+		if ua, ub := a.User(), b.User(); ua != ub {
+			t.Fatalf("expected identical users, got %+v and %+v", ua, ub)
+		}
+		if pa, pb := a.Product(), b.Product(); pa != pb {
+			t.Fatalf("expected identical products, got %+v and %+v", pa, pb)
+		}
+	}
+}
+
+// TestGenerator_Plausible tests that generated values are populated and in range
+func TestGenerator_Plausible(t *testing.T) {
+	breeds := map[string]bool{}
+	for _, breed := range dogBreeds {
+		breeds[breed] = true
+	}
+
+	gen := NewGenerator(1)
+	for _, u := range gen.Users(50) {
+		if u.Name == "" || u.LastName == "" || u.Address == "" {
+			t.Errorf("expected populated user, got %+v", u)
+		}
+		if !breeds[u.FavoriteDogBreed] {
+			t.Errorf("expected a real dog breed, got %q", u.FavoriteDogBreed)
+		}
+	}
+
+	outOfStock := 0
+	for _, p := range gen.Products(200) {
+		if p.Name == "" || p.Type == "" || p.Department == "" {
+			t.Errorf("expected populated product, got %+v", p)
+		}
+		if p.Price <= 0 || p.Stock < 0 {
+			t.Errorf("expected positive price and non-negative stock, got %+v", p)
+		}
+		if p.Stock == 0 {
+			outOfStock++
+		}
+	}
+	if outOfStock == 0 || outOfStock > 60 {
+		t.Errorf("expected a small share of out-of-stock products, got %d of 200", outOfStock)
+	}
+}
+
+// TestPopulateUsers tests pushing generated users through the client
+func TestPopulateUsers(t *testing.T) {
+	created := 0
+	// Create a mock server that accepts users
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/user" {
+			t.Errorf("expected POST /user, got %s %s", r.Method, r.URL.Path)
+		}
+		created++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1", "name": "Generated"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	users, err := client.PopulateUsers(NewGenerator(1), 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(users) != 5 || created != 5 {
+		t.Errorf("expected 5 users created, got %d returned and %d requests", len(users), created)
+	}
+}
@@ -0,0 +1,116 @@
+package mockclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// CompressionStats - Byte counts before and after compression
+type CompressionStats struct {
+	// RequestBytes is the size of request bodies before compression
+	RequestBytes int64
+	// RequestBytesSent is the size of request bodies as sent
+	RequestBytesSent int64
+	// ResponseBytesReceived is the size of response bodies as received
+	ResponseBytesReceived int64
+	// ResponseBytes is the size of response bodies after decompression
+	ResponseBytes int64
+}
+
+// BytesSaved - Returns how many bytes compression kept off the wire in both directions
+func (s CompressionStats) BytesSaved() int64 {
+	return s.RequestBytes - s.RequestBytesSent + s.ResponseBytes - s.ResponseBytesReceived
+}
+
+// compressionCounters - Running totals behind CompressionStats
+type compressionCounters struct {
+	requestRaw       atomic.Int64
+	requestSent      atomic.Int64
+	responseReceived atomic.Int64
+	responseDecoded  atomic.Int64
+}
+
+// CompressionStats - Returns the bytes sent and received by the client so far
+func (c *Client) CompressionStats() CompressionStats {
+	return CompressionStats{
+		RequestBytes:          c.compression.requestRaw.Load(),
+		RequestBytesSent:      c.compression.requestSent.Load(),
+		ResponseBytesReceived: c.compression.responseReceived.Load(),
+		ResponseBytes:         c.compression.responseDecoded.Load(),
+	}
+}
+
+// prepareCompression - Sets Accept-Encoding and gzips the request body when enabled
+func (c *Client) prepareCompression(req *http.Request) error {
+	if c.AcceptGzip {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	c.compression.requestRaw.Add(int64(len(raw)))
+
+	payload := raw
+	if c.GzipRequests {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(raw); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		payload = buf.Bytes()
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	c.compression.requestSent.Add(int64(len(payload)))
+
+	req.Body = io.NopCloser(bytes.NewReader(payload))
+	req.ContentLength = int64(len(payload))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(payload)), nil
+	}
+
+	return nil
+}
+
+// decompress - Returns a reader over the decoded response body, counting the bytes received
+func (c *Client) decompress(res *http.Response) (io.Reader, error) {
+	received := &countingReader{r: res.Body, n: &c.compression.responseReceived}
+	if !c.AcceptGzip || !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return received, nil
+	}
+
+	zr, err := gzip.NewReader(received)
+	if err != nil {
+		// An empty body (e.g. 204 or 304) carries nothing to decode
+		if err == io.EOF {
+			return received, nil
+		}
+		return nil, err
+	}
+
+	return zr, nil
+}
+
+// countingReader - Adds the number of bytes read to n
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,90 @@
+// gzip_test.go
+
+package mockclient
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestAcceptGzip tests transparent decompression of a gzip response
+func TestAcceptGzip(t *testing.T) {
+	var items []string
+	for i := 1; i <= 100; i++ {
+		items = append(items, fmt.Sprintf(`{"id": "%d", "name": "Product %d", "price": "10.00", "stock": "50", "department": "Electronics"}`, i, i))
+	}
+	payload := "[" + strings.Join(items, ",") + "]"
+
+	// Create a mock server that compresses its responses
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(payload))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.AcceptGzip = true
+	products, err := client.GetProducts()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(products) != 100 {
+		t.Errorf("expected 100 products, got %d", len(products))
+	}
+
+	stats := client.CompressionStats()
+	if stats.ResponseBytes != int64(len(payload)) {
+		t.Errorf("expected %d decoded response bytes, got %d", len(payload), stats.ResponseBytes)
+	}
+	if stats.ResponseBytesReceived >= stats.ResponseBytes || stats.BytesSaved() <= 0 {
+		t.Errorf("expected compression to save bytes, got %+v", stats)
+	}
+}
+
+// TestGzipRequests tests that write bodies are sent gzip-encoded
+func TestGzipRequests(t *testing.T) {
+	// Create a mock server that decodes gzip request bodies
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected Content-Encoding gzip, got %q", r.Header.Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("expected gzip request body, got %v", err)
+		}
+
+		var user User
+		if err := json.NewDecoder(zr).Decode(&user); err != nil {
+			t.Fatalf("expected no error decoding request body, got %v", err)
+		}
+		if user.Name != "Alice" {
+			t.Errorf("expected user name 'Alice', got '%s'", user.Name)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1", "name": "Alice"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.GzipRequests = true
+	if _, err := client.CreateUser(&User{Name: "Alice", Address: strings.Repeat("Main St ", 50)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	stats := client.CompressionStats()
+	if stats.RequestBytesSent >= stats.RequestBytes {
+		t.Errorf("expected compressed request to be smaller, got %+v", stats)
+	}
+}
@@ -0,0 +1,479 @@
+package mockclient
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UserRow - A user read from an import file and the line it starts on
+type UserRow struct {
+	Line int
+	User User
+	// Fields lists the JSON names of the fields the row gives; an upsert only changes these
+	Fields []string
+}
+
+// ProductRow - A product read from an import file and the line it starts on
+type ProductRow struct {
+	Line    int
+	Product Product
+	// Fields lists the JSON names of the fields the row gives; an upsert only changes these
+	Fields []string
+}
+
+// RowError - A problem with one row of an import file
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError - Returned when rows of an import file are invalid; nothing is written
+type ValidationError struct {
+	Rows []RowError
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d invalid row(s)", len(e.Rows))
+	for _, row := range e.Rows {
+		fmt.Fprintf(&sb, "\n  %s", row.Error())
+	}
+	return sb.String()
+}
+
+// ImportOptions - Controls ImportUsers and ImportProducts
+type ImportOptions struct {
+	// UserKey and ProductKey, when set, update the existing record with the same key instead of
+	// creating a new one, like UpsertUser and UpsertProduct
+	UserKey    func(User) string
+	ProductKey func(Product) string
+	// DryRun validates the rows and reports what would be done without writing anything
+	DryRun bool
+	// Progress, when set, is called after each row is processed
+	Progress func(ImportProgress)
+}
+
+// ImportProgress - The outcome of one imported row
+type ImportProgress struct {
+	Done   int
+	Total  int
+	Line   int
+	Action UpsertAction
+	Err    error
+}
+
+// ImportReport - The outcome of an import
+type ImportReport struct {
+	DryRun    bool
+	Total     int
+	Created   int
+	Updated   int
+	Unchanged int
+	Failed    []RowError
+}
+
+var (
+	userImportFields    = []string{"name", "lastName", "address", "favoriteDogBreed"}
+	productImportFields = []string{"name", "price", "stock", "type", "department"}
+	// ignoredImportFields are server-managed, so exported files can be imported again
+	ignoredImportFields = []string{"id", "createdAt"}
+)
+
+// ReadUsers - Reads users from CSV (with a header row naming the fields) or NDJSON.
+// Invalid rows are reported together as a *ValidationError alongside the valid rows.
+func ReadUsers(r io.Reader, format Format) ([]UserRow, error) {
+	rows := []UserRow{}
+	err := readImport(r, format, userImportFields, func(line int, fields map[string]string) error {
+		user := User{}
+		for name, value := range fields {
+			switch name {
+			case "name":
+				user.Name = value
+			case "lastName":
+				user.LastName = value
+			case "address":
+				user.Address = value
+			case "favoriteDogBreed":
+				user.FavoriteDogBreed = value
+			}
+		}
+		if user.Name == "" {
+			return errors.New("name is required")
+		}
+		rows = append(rows, UserRow{Line: line, User: user, Fields: fieldNames(fields)})
+		return nil
+	})
+	return rows, err
+}
+
+// ReadProducts - Reads products from CSV (with a header row naming the fields) or NDJSON.
+// Price and stock accept plain numbers; price may start with "$" and stock must be a whole number.
+// Invalid rows are reported together as a *ValidationError alongside the valid rows.
+func ReadProducts(r io.Reader, format Format) ([]ProductRow, error) {
+	rows := []ProductRow{}
+	err := readImport(r, format, productImportFields, func(line int, fields map[string]string) error {
+		product := Product{}
+		for name, value := range fields {
+			switch name {
+			case "name":
+				product.Name = value
+			case "price":
+				price, err := strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+				if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+					return fmt.Errorf("invalid price %q", value)
+				}
+				product.Price = price
+			case "stock":
+				stock, err := strconv.Atoi(value)
+				if err != nil || stock < 0 {
+					return fmt.Errorf("invalid stock %q", value)
+				}
+				product.Stock = stock
+			case "type":
+				product.Type = value
+			case "department":
+				product.Department = value
+			}
+		}
+		if product.Name == "" {
+			return errors.New("name is required")
+		}
+		rows = append(rows, ProductRow{Line: line, Product: product, Fields: fieldNames(fields)})
+		return nil
+	})
+	return rows, err
+}
+
+// ImportUsers - Reads users from r and creates them, or upserts them when opts.UserKey is set.
+// Nothing is written if any row is invalid; failed writes are reported per row and do not stop the import.
+func (c *Client) ImportUsers(r io.Reader, format Format, opts ImportOptions) (*ImportReport, error) {
+	rows, err := ReadUsers(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var current map[string]User
+	if opts.UserKey != nil {
+		if err := checkDuplicateKeys(len(rows), func(i int) (int, string) { return rows[i].Line, opts.UserKey(rows[i].User) }); err != nil {
+			return nil, err
+		}
+		users, err := c.GetUsers()
+		if err != nil {
+			return nil, err
+		}
+		if current, err = indexByKey(users, opts.UserKey, "users"); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &ImportReport{DryRun: opts.DryRun, Total: len(rows)}
+	for i, row := range rows {
+		user := row.User
+		existing, found := current[keyOf(opts.UserKey, user)]
+		action, err := importRecord(opts.DryRun, found, existing, user, row.Fields,
+			func() error {
+				_, err := c.CreateUser(&user)
+				return err
+			},
+			func(changes []FieldChange) error {
+				if err := c.patchRecord(fmt.Sprintf("%s/user/%d", c.HostURL, existing.ID), changes, &User{}); err != nil {
+					return err
+				}
+				c.RecordCache.invalidate("user", existing.ID)
+				return nil
+			})
+		report.record(opts, i, row.Line, action, err)
+	}
+	return report, nil
+}
+
+// ImportProducts - Reads products from r and creates them, or upserts them when opts.ProductKey is set.
+// Nothing is written if any row is invalid; failed writes are reported per row and do not stop the import.
+func (c *Client) ImportProducts(r io.Reader, format Format, opts ImportOptions) (*ImportReport, error) {
+	rows, err := ReadProducts(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var current map[string]Product
+	if opts.ProductKey != nil {
+		if err := checkDuplicateKeys(len(rows), func(i int) (int, string) { return rows[i].Line, opts.ProductKey(rows[i].Product) }); err != nil {
+			return nil, err
+		}
+		products, err := c.GetProducts()
+		if err != nil {
+			return nil, err
+		}
+		if current, err = indexByKey(products, opts.ProductKey, "products"); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &ImportReport{DryRun: opts.DryRun, Total: len(rows)}
+	for i, row := range rows {
+		product := row.Product
+		existing, found := current[keyOf(opts.ProductKey, product)]
+		action, err := importRecord(opts.DryRun, found, existing, product, row.Fields,
+			func() error {
+				_, err := c.CreateProduct(&product)
+				return err
+			},
+			func(changes []FieldChange) error {
+				if err := c.patchRecord(fmt.Sprintf("%s/products/%d", c.HostURL, existing.ID), changes, &Product{}); err != nil {
+					return err
+				}
+				c.RecordCache.invalidate("product", existing.ID)
+				return nil
+			})
+		report.record(opts, i, row.Line, action, err)
+	}
+	return report, nil
+}
+
+// keyOf - Returns the key of record, or "" when there is no key function
+func keyOf[T any](key func(T) string, record T) string {
+	if key == nil {
+		return ""
+	}
+	return key(record)
+}
+
+// importRecord - Creates a record, or patches the given fields that differ from the existing one when found
+func importRecord(dryRun, found bool, existing, desired interface{}, fields []string, create func() error, patch func([]FieldChange) error) (UpsertAction, error) {
+	if !found {
+		if dryRun {
+			return UpsertCreated, nil
+		}
+		return UpsertCreated, create()
+	}
+
+	changes, err := diffFields(existing, desired, fields)
+	if err != nil {
+		return UpsertUpdated, err
+	}
+	if len(changes) == 0 {
+		return UpsertUnchanged, nil
+	}
+	if dryRun {
+		return UpsertUpdated, nil
+	}
+	return UpsertUpdated, patch(changes)
+}
+
+// fieldNames - Returns the names of a row's fields, sorted
+func fieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// record - Counts the outcome of one row and reports progress
+func (r *ImportReport) record(opts ImportOptions, i, line int, action UpsertAction, err error) {
+	if err != nil {
+		r.Failed = append(r.Failed, RowError{Line: line, Err: err})
+	} else {
+		switch action {
+		case UpsertCreated:
+			r.Created++
+		case UpsertUpdated:
+			r.Updated++
+		case UpsertUnchanged:
+			r.Unchanged++
+		}
+	}
+
+	if opts.Progress != nil {
+		opts.Progress(ImportProgress{Done: i + 1, Total: r.Total, Line: line, Action: action, Err: err})
+	}
+}
+
+// checkDuplicateKeys - Fails when two rows share a key, since the second would silently update the first
+func checkDuplicateKeys(n int, row func(i int) (int, string)) error {
+	seen := map[string]int{}
+	verr := &ValidationError{}
+	for i := 0; i < n; i++ {
+		line, key := row(i)
+		if first, dup := seen[key]; dup {
+			verr.Rows = append(verr.Rows, RowError{Line: line, Err: fmt.Errorf("same key as line %d", first)})
+			continue
+		}
+		seen[key] = line
+	}
+	if len(verr.Rows) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// readImport - Parses CSV or NDJSON into field maps keyed by JSON name and passes each row to add.
+// Row errors, including those returned by add, are collected into a *ValidationError.
+func readImport(r io.Reader, format Format, allowed []string, add func(line int, fields map[string]string) error) error {
+	verr := &ValidationError{}
+	addRow := func(line int, fields map[string]string) {
+		if err := add(line, fields); err != nil {
+			verr.Rows = append(verr.Rows, RowError{Line: line, Err: err})
+		}
+	}
+
+	var err error
+	switch format {
+	case FormatCSV:
+		err = readCSVImport(r, allowed, addRow, verr)
+	case FormatNDJSON:
+		err = readNDJSONImport(r, allowed, addRow, verr)
+	default:
+		return fmt.Errorf("%w %q for import", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return err
+	}
+	if len(verr.Rows) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// readCSVImport - Reads a CSV file whose header names the fields; header names are matched
+// to JSON names ignoring case, spaces, dashes and underscores
+func readCSVImport(r io.Reader, allowed []string, addRow func(int, map[string]string), verr *ValidationError) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	fields := make([]string, len(header))
+	for i, h := range header {
+		name, ok := matchImportField(h, allowed)
+		if !ok {
+			verr.Rows = append(verr.Rows, RowError{Line: 1, Err: fmt.Errorf("unknown column %q", h)})
+		}
+		fields[i] = name
+	}
+	if len(verr.Rows) > 0 {
+		return nil
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			verr.Rows = append(verr.Rows, RowError{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := cr.FieldPos(0)
+		values := map[string]string{}
+		for i, v := range record {
+			if v = strings.TrimSpace(v); v != "" && fields[i] != "" {
+				values[fields[i]] = v
+			}
+		}
+		addRow(line, values)
+	}
+}
+
+// readNDJSONImport - Reads one JSON object per line; values may be strings or numbers
+func readNDJSONImport(r io.Reader, allowed []string, addRow func(int, map[string]string), verr *ValidationError) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		raw := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			verr.Rows = append(verr.Rows, RowError{Line: line, Err: err})
+			continue
+		}
+
+		values := map[string]string{}
+		var rowErr error
+		for k, v := range raw {
+			name, ok := matchImportField(k, allowed)
+			if !ok {
+				rowErr = fmt.Errorf("unknown field %q", k)
+				break
+			}
+			if name == "" {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				if v = strings.TrimSpace(v); v != "" {
+					values[name] = v
+				}
+			case float64:
+				values[name] = strconv.FormatFloat(v, 'f', -1, 64)
+			case nil:
+			default:
+				rowErr = fmt.Errorf("field %q must be a string or number", k)
+			}
+		}
+		if rowErr != nil {
+			verr.Rows = append(verr.Rows, RowError{Line: line, Err: rowErr})
+			continue
+		}
+		addRow(line, values)
+	}
+	return scanner.Err()
+}
+
+// matchImportField - Maps a column or key to a JSON field name; server-managed fields map to ""
+func matchImportField(name string, allowed []string) (string, bool) {
+	norm := normalizeFieldName(name)
+	for _, field := range ignoredImportFields {
+		if normalizeFieldName(field) == norm {
+			return "", true
+		}
+	}
+	for _, field := range allowed {
+		if normalizeFieldName(field) == norm {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// normalizeFieldName - Lower-cases a name and drops spaces, dashes and underscores
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.TrimSpace(name)))
+}
@@ -0,0 +1,228 @@
+// import_test.go
+
+package mockclient
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadProducts_CSV tests header mapping and price/stock conversion
+func TestReadProducts_CSV(t *testing.T) {
+	input := "\ufeffName,Price,Stock,department,id\n" +
+		"Ball,$5.50,10,Toys,7\n" +
+		"\"Kite, large\", 12 ,0,Toys,\n"
+
+	rows, err := ReadProducts(strings.NewReader(input), FormatCSV)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	expected := ProductRow{Line: 2, Product: Product{Name: "Ball", Price: 5.5, Stock: 10, Department: "Toys"}, Fields: []string{"department", "name", "price", "stock"}}
+	if !reflect.DeepEqual(rows[0], expected) {
+		t.Errorf("expected %+v, got %+v", expected, rows[0])
+	}
+	if rows[1].Line != 3 || rows[1].Product.Name != "Kite, large" || rows[1].Product.Price != 12 {
+		t.Errorf("expected quoted name and trimmed price on line 3, got %+v", rows[1])
+	}
+}
+
+// TestReadProducts_ValidationErrors tests that every invalid row is reported with its line number
+func TestReadProducts_ValidationErrors(t *testing.T) {
+	input := "name,price,stock\n" +
+		"Ball,5,10\n" +
+		"Kite,cheap,1\n" +
+		",3,1\n" +
+		"Doll,3,-2\n" +
+		"Top,NaN,1\n" +
+		"Yoyo,1e309,1\n"
+
+	_, err := ReadProducts(strings.NewReader(input), FormatCSV)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+
+	expected := []string{`line 3: invalid price "cheap"`, "line 4: name is required", `line 5: invalid stock "-2"`, `line 6: invalid price "NaN"`, `line 7: invalid price "1e309"`}
+	if len(verr.Rows) != len(expected) {
+		t.Fatalf("expected %d row errors, got %v", len(expected), err)
+	}
+	for i := range expected {
+		if verr.Rows[i].Error() != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], verr.Rows[i].Error())
+		}
+	}
+
+	if _, err := ReadProducts(strings.NewReader("name,color\nBall,red\n"), FormatCSV); err == nil || !strings.Contains(err.Error(), `line 1: unknown column "color"`) {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+}
+
+// TestReadUsers_NDJSON tests reading users from NDJSON
+func TestReadUsers_NDJSON(t *testing.T) {
+	input := `{"id": "4", "name": "Alice", "lastName": "Smith"}` + "\n\n" +
+		`{"name": "Bob", "favoriteDogBreed": "Beagle"}` + "\n" +
+		`{"name": "Eve", "age": 30}` + "\n"
+
+	rows, err := ReadUsers(strings.NewReader(input), FormatNDJSON)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Rows) != 1 || verr.Rows[0].Line != 4 {
+		t.Fatalf("expected one error on line 4, got %v", err)
+	}
+	if len(rows) != 2 || rows[1].Line != 3 || rows[1].User.FavoriteDogBreed != "Beagle" {
+		t.Errorf("expected the two valid rows, got %+v", rows)
+	}
+
+	// CSV headers match field names ignoring case and separators
+	rows, err = ReadUsers(strings.NewReader("Name,Last Name,favorite_dog_breed\nAlice,Smith,Poodle\n"), FormatCSV)
+	if err != nil || len(rows) != 1 || rows[0].User.LastName != "Smith" || rows[0].User.FavoriteDogBreed != "Poodle" {
+		t.Errorf("expected the two valid rows, got %+v", rows)
+	}
+}
+
+// TestImportProducts_Upsert tests upserting by key with progress reporting, and the dry run
+func TestImportProducts_Upsert(t *testing.T) {
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "1", "name": "Ball", "price": "5.000000", "stock": "10", "department": "Toys"},
+				{"id": "2", "name": "Kite", "price": "12.000000", "stock": "3", "department": "Toys"}]`))
+		case http.MethodPost:
+			writes = append(writes, "POST "+r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "3", "name": "Doll", "price": "8.000000", "stock": "4", "department": "Toys"}`))
+		case http.MethodPatch:
+			writes = append(writes, "PATCH "+r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "2", "name": "Kite", "price": "12.000000", "stock": "9", "department": "Toys"}`))
+		}
+	}))
+	defer server.Close()
+
+	input := "name,price,stock,department\n" +
+		"Ball,5,10,Toys\n" +
+		"Kite,12,9,Toys\n" +
+		"Doll,8,4,Toys\n"
+
+	client, _ := NewClient(&server.URL)
+	dry, err := client.ImportProducts(strings.NewReader(input), FormatCSV, ImportOptions{ProductKey: ProductNameDepartmentKey, DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(writes) != 0 {
+		t.Fatalf("expected no writes during a dry run, got %v", writes)
+	}
+
+	var progress []ImportProgress
+	report, err := client.ImportProducts(strings.NewReader(input), FormatCSV, ImportOptions{
+		ProductKey: ProductNameDepartmentKey,
+		Progress:   func(p ImportProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, r := range []*ImportReport{dry, report} {
+		if r.Total != 3 || r.Created != 1 || r.Updated != 1 || r.Unchanged != 1 || len(r.Failed) != 0 {
+			t.Errorf("expected 1 created, 1 updated and 1 unchanged, got %+v", r)
+		}
+	}
+	if strings.Join(writes, ", ") != "PATCH /products/2, POST /products" {
+		t.Errorf("expected a patch and a create, got %v", writes)
+	}
+	if len(progress) != 3 || progress[2].Done != 3 || progress[2].Line != 4 || progress[2].Action != UpsertCreated {
+		t.Errorf("expected progress for each row, got %+v", progress)
+	}
+}
+
+// TestImportProducts_UpsertMissingColumns tests that an upsert only changes the columns a row gives
+func TestImportProducts_UpsertMissingColumns(t *testing.T) {
+	var patches []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "1", "name": "Ball", "price": "5.000000", "stock": "10", "department": "Toys"},
+				{"id": "2", "name": "Kite", "price": "12.000000", "stock": "3", "type": "Outdoor", "department": "Toys"}]`))
+		case http.MethodPatch:
+			fields := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+				t.Fatalf("expected no error decoding request body, got %v", err)
+			}
+			patches = append(patches, fields)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "price": "0", "stock": "0"}`))
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	opts := ImportOptions{ProductKey: ProductNameDepartmentKey}
+
+	// No price or stock column, and an empty stock cell, leave the server's values alone
+	input := "name,department,type,stock\n" +
+		"Ball,Toys,Kids,\n" +
+		"Kite,Toys,Outdoor,0\n"
+	report, err := client.ImportProducts(strings.NewReader(input), FormatCSV, opts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Updated != 2 || report.Unchanged != 0 || len(report.Failed) != 0 {
+		t.Errorf("expected 2 updated, got %+v", report)
+	}
+	expected := []map[string]interface{}{{"type": "Kids"}, {"stock": "0"}}
+	if !reflect.DeepEqual(patches, expected) {
+		t.Errorf("expected patches %v, got %v", expected, patches)
+	}
+
+	patches = nil
+	report, err = client.ImportProducts(strings.NewReader("name,department\nBall,Toys\n"), FormatCSV, ImportOptions{ProductKey: ProductNameDepartmentKey, DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Unchanged != 1 || report.Updated != 0 {
+		t.Errorf("expected the row to be unchanged, got %+v", report)
+	}
+}
+
+// TestImportUsers_Failures tests that failed writes are reported per row without stopping the import
+func TestImportUsers_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "Bob") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1", "name": "Alice"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	report, err := client.ImportUsers(strings.NewReader("name\nBob\nAlice\n"), FormatCSV, ImportOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Created != 1 || len(report.Failed) != 1 || report.Failed[0].Line != 2 {
+		t.Errorf("expected line 2 to fail and line 3 to be created, got %+v", report)
+	}
+
+	// Duplicate keys are rejected before anything is written
+	_, err = client.ImportUsers(strings.NewReader("name\nAlice\nAlice\n"), FormatCSV, ImportOptions{UserKey: UserFullNameKey})
+	if err == nil || !strings.Contains(err.Error(), "line 3: same key as line 2") {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+}
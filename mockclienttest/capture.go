@@ -0,0 +1,241 @@
+package mockclienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// CapturedRequest - A request received by a Capture
+type CapturedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Capture - An http.Handler that records every request before passing it to Next
+type Capture struct {
+	// Next answers captured requests; when nil every request gets a 200 with an empty JSON object
+	Next http.Handler
+
+	mu       sync.Mutex
+	requests []CapturedRequest
+}
+
+// NewCapture - Creates a capturing handler in front of next
+func NewCapture(next http.Handler) *Capture {
+	return &Capture{Next: next}
+}
+
+// ServeHTTP - Records the request and delegates to Next
+func (c *Capture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	c.mu.Lock()
+	c.requests = append(c.requests, CapturedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	c.mu.Unlock()
+
+	if c.Next == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "{}")
+		return
+	}
+	c.Next.ServeHTTP(w, r)
+}
+
+// Requests - Returns the captured requests in arrival order
+func (c *Capture) Requests() []CapturedRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CapturedRequest(nil), c.requests...)
+}
+
+// AssertCount - Fails the test unless exactly n requests were captured
+func (c *Capture) AssertCount(t testing.TB, n int) {
+	t.Helper()
+	if got := len(c.Requests()); got != n {
+		t.Errorf("expected %d request(s), got %d:%s", n, got, c.summary())
+	}
+}
+
+// Request - Returns assertions on the i-th captured request (0-based), failing the test now if there is none
+func (c *Capture) Request(t testing.TB, i int) *RequestAssertion {
+	t.Helper()
+	requests := c.Requests()
+	if i < 0 || i >= len(requests) {
+		t.Fatalf("expected a request #%d, got %d request(s):%s", i+1, len(requests), c.summary())
+	}
+	return &RequestAssertion{t: t, index: i, req: requests[i]}
+}
+
+// Last - Returns assertions on the most recent captured request
+func (c *Capture) Last(t testing.TB) *RequestAssertion {
+	t.Helper()
+	return c.Request(t, len(c.Requests())-1)
+}
+
+// summary - Lists the captured requests for failure messages
+func (c *Capture) summary() string {
+	var sb strings.Builder
+	for i, r := range c.Requests() {
+		fmt.Fprintf(&sb, "\n  #%d %s %s", i+1, r.Method, r.Path)
+	}
+	return sb.String()
+}
+
+// RequestAssertion - Chainable assertions on one captured request
+type RequestAssertion struct {
+	t     testing.TB
+	index int
+	req   CapturedRequest
+}
+
+// Captured - Returns the underlying request
+func (a *RequestAssertion) Captured() CapturedRequest {
+	return a.req
+}
+
+// Method - Asserts the request method
+func (a *RequestAssertion) Method(method string) *RequestAssertion {
+	a.t.Helper()
+	if a.req.Method != method {
+		a.errorf("expected method %s, got %s", method, a.req.Method)
+	}
+	return a
+}
+
+// Path - Asserts the request path
+func (a *RequestAssertion) Path(path string) *RequestAssertion {
+	a.t.Helper()
+	if a.req.Path != path {
+		a.errorf("expected path %s, got %s", path, a.req.Path)
+	}
+	return a
+}
+
+// Query - Asserts the value of a query parameter
+func (a *RequestAssertion) Query(key, value string) *RequestAssertion {
+	a.t.Helper()
+	if got, ok := a.req.Query[key]; !ok || a.req.Query.Get(key) != value {
+		a.errorf("expected query %s=%q, got %q", key, value, got)
+	}
+	return a
+}
+
+// Header - Asserts the value of a request header
+func (a *RequestAssertion) Header(key, value string) *RequestAssertion {
+	a.t.Helper()
+	if got := a.req.Header.Get(key); got != value {
+		a.errorf("expected header %s: %q, got %q", key, value, got)
+	}
+	return a
+}
+
+// JSON - Asserts the body is JSON equal to expected after encoding it with its own marshaler,
+// so User and Product values are compared in the wire format (string id, price and stock)
+func (a *RequestAssertion) JSON(expected interface{}) *RequestAssertion {
+	a.t.Helper()
+
+	want, err := toJSONValue(expected)
+	if err != nil {
+		a.t.Fatalf("cannot encode expected body: %v", err)
+	}
+	var got interface{}
+	if err := json.Unmarshal(a.req.Body, &got); err != nil {
+		a.errorf("expected a JSON body, got %q: %v", a.req.Body, err)
+		return a
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		a.errorf("body mismatch:\n%s", jsonDiff(want, got))
+	}
+	return a
+}
+
+// errorf - Reports a failure prefixed with the request it concerns
+func (a *RequestAssertion) errorf(format string, args ...interface{}) {
+	a.t.Helper()
+	a.t.Errorf("request #%d (%s %s): %s", a.index+1, a.req.Method, a.req.Path, fmt.Sprintf(format, args...))
+}
+
+// toJSONValue - Encodes v and decodes it into generic JSON values; strings and []byte are taken as raw JSON
+func toJSONValue(v interface{}) (interface{}, error) {
+	var data []byte
+	switch raw := v.(type) {
+	case string:
+		data = []byte(raw)
+	case []byte:
+		data = raw
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var out interface{}
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
+// jsonDiff - Describes the differences between two JSON values, field by field for objects
+func jsonDiff(want, got interface{}) string {
+	wantObj, ok1 := want.(map[string]interface{})
+	gotObj, ok2 := got.(map[string]interface{})
+	if !ok1 || !ok2 {
+		w, _ := json.Marshal(want)
+		g, _ := json.Marshal(got)
+		return fmt.Sprintf("  - expected: %s\n  + actual:   %s", w, g)
+	}
+
+	keys := map[string]bool{}
+	for k := range wantObj {
+		keys[k] = true
+	}
+	for k := range gotObj {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var sb strings.Builder
+	for _, k := range sorted {
+		w, inWant := wantObj[k]
+		g, inGot := gotObj[k]
+		switch {
+		case !inGot:
+			fmt.Fprintf(&sb, "  %s: missing, expected %s\n", k, mustJSON(w))
+		case !inWant:
+			fmt.Fprintf(&sb, "  %s: unexpected %s\n", k, mustJSON(g))
+		case !reflect.DeepEqual(w, g):
+			fmt.Fprintf(&sb, "  %s: expected %s, got %s\n", k, mustJSON(w), mustJSON(g))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// mustJSON - Encodes a decoded JSON value for display
+func mustJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
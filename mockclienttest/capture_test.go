@@ -0,0 +1,64 @@
+// capture_test.go
+
+package mockclienttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// TestCapture tests assertions on requests made by the client
+func TestCapture(t *testing.T) {
+	capture := NewCapture(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "9"}`))
+	}))
+	server := httptest.NewServer(capture)
+	defer server.Close()
+
+	client, _ := mockclient.NewClient(&server.URL)
+	product := mockclient.Product{ID: 1, Name: "Ball", Price: 5, Stock: 9}
+	if _, err := client.UpdateProduct(&product); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	capture.AssertCount(t, 1)
+	capture.Last(t).
+		Method(http.MethodPatch).
+		Path("/products/1").
+		Header("Content-Type", "application/json").
+		JSON(product).
+		JSON(`{"id": "1", "name": "Ball", "price": "5.000000", "stock": "9"}`)
+}
+
+// TestCapture_FailureMessages tests that mismatches name the request and the differing fields
+func TestCapture_FailureMessages(t *testing.T) {
+	capture := NewCapture(nil)
+	server := httptest.NewServer(capture)
+	defer server.Close()
+
+	http.Post(server.URL+"/user?dry=1", "application/json", strings.NewReader(`{"id": "0", "name": "Bob", "extra": true}`))
+
+	tb := &fakeTB{}
+	capture.Request(tb, 0).
+		Method(http.MethodPut).
+		Query("dry", "0").
+		JSON(mockclient.User{Name: "Alice"})
+
+	if len(tb.errors) != 3 {
+		t.Fatalf("expected 3 failures, got %v", tb.errors)
+	}
+	for i, want := range []string{
+		"request #1 (POST /user): expected method PUT, got POST",
+		`expected query dry="0", got ["1"]`,
+		"extra: unexpected true\n  name: expected \"Alice\", got \"Bob\"",
+	} {
+		if !strings.Contains(tb.errors[i], want) {
+			t.Errorf("expected failure %d to contain %q, got %q", i+1, want, tb.errors[i])
+		}
+	}
+}
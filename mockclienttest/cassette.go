@@ -0,0 +1,273 @@
+package mockclienttest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// Interaction - One recorded request/response pair, stored as a line of a JSONL cassette
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest - The parts of a request that are recorded and matched on
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	Path         string      `json:"path"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+// RecordedResponse - A recorded response
+type RecordedResponse struct {
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+// Matcher - Reports whether a recorded request may answer an incoming one
+type Matcher func(req *http.Request, body []byte, recorded RecordedRequest) bool
+
+// MatchMethodPath - Matches on method and path, including the query string
+func MatchMethodPath(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	return req.Method == recorded.Method && req.URL.RequestURI() == recorded.Path
+}
+
+// MatchMethodPathBody - Matches on method, path and exact body
+func MatchMethodPathBody(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	if !MatchMethodPath(req, body, recorded) {
+		return false
+	}
+	recordedBody, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	return err == nil && bytes.Equal(body, recordedBody)
+}
+
+// DefaultRedactedHeaders - Headers carrying credentials, which Recorder does not write to cassettes by default
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactedValue replaces the values of redacted headers in a cassette
+const redactedValue = "REDACTED"
+
+// Recorder - A RoundTripper that forwards requests and appends each interaction to a JSONL cassette
+type Recorder struct {
+	// RedactHeaders names request and response headers whose values are replaced with "REDACTED"
+	// in the cassette; defaults to DefaultRedactedHeaders. The forwarded request is not changed.
+	RedactHeaders []string
+
+	next http.RoundTripper
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder - Creates a recorder writing to path, truncating any existing cassette.
+// A nil next uses http.DefaultTransport.
+func NewRecorder(path string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{RedactHeaders: DefaultRedactedHeaders, next: next, file: file}, nil
+}
+
+// Close - Closes the cassette file
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// RoundTrip - Forwards the request and records it with its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := drainBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request:  RecordedRequest{Method: req.Method, Path: req.URL.RequestURI(), Header: r.redact(req.Header)},
+		Response: RecordedResponse{Status: res.StatusCode, Header: r.redact(res.Header)},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(resBody)
+
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// redact - Returns a copy of header with the values of RedactHeaders replaced
+func (r *Recorder) redact(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range r.RedactHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			redacted := make([]string, len(values))
+			for i := range redacted {
+				redacted[i] = redactedValue
+			}
+			header[http.CanonicalHeaderKey(name)] = redacted
+		}
+	}
+	return header
+}
+
+// Replayer - A RoundTripper that answers requests from a cassette without touching the network.
+// Each interaction is used at most once, in recorded order among those that match.
+type Replayer struct {
+	// Match selects recorded interactions; defaults to MatchMethodPath
+	Match Matcher
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer - Loads a cassette written by Recorder
+func NewReplayer(path string) (*Replayer, error) {
+	interactions, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{Match: MatchMethodPath, interactions: interactions, used: make([]bool, len(interactions))}, nil
+}
+
+// LoadCassette - Reads every interaction of a JSONL cassette
+func LoadCassette(path string) ([]Interaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	interactions := []Interaction{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		interaction := Interaction{}
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, scanner.Err()
+}
+
+// Remaining - Returns the number of interactions not yet replayed
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+// RoundTrip - Answers the request with the first unused matching interaction
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	match := r.Match
+	if match == nil {
+		match = MatchMethodPath
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !match(req, body, interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		resBody, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(resBody)),
+			ContentLength: int64(len(resBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("mockclienttest: no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// drainBody - Reads a body fully and replaces it with an equivalent unread copy
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// encodeBody - Stores text bodies as-is and binary ones (e.g. gzip) as base64
+func encodeBody(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return string(data), ""
+	}
+	return base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+// decodeBody - Reverses encodeBody
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
@@ -0,0 +1,133 @@
+// cassette_test.go
+
+package mockclienttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// TestRecordAndReplay tests that recorded traffic can be replayed with the server gone
+func TestRecordAndReplay(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	server := NewServer()
+	recorder, err := NewRecorder(cassette, nil)
+	if err != nil {
+		t.Fatalf("expected no error creating recorder, got %v", err)
+	}
+	client := server.NewClient()
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	if _, err := client.CreateUser(&mockclient.User{Name: "Alice"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.GetUsers(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	recorder.Close()
+	server.Close()
+
+	replayer, err := NewReplayer(cassette)
+	if err != nil {
+		t.Fatalf("expected no error loading cassette, got %v", err)
+	}
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	created, err := client.CreateUser(&mockclient.User{Name: "Alice"})
+	if err != nil {
+		t.Fatalf("expected no error replaying create, got %v", err)
+	}
+	if created.ID != 1 {
+		t.Errorf("expected replayed user ID 1, got %d", created.ID)
+	}
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatalf("expected no error replaying list, got %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "Alice" {
+		t.Errorf("expected replayed user Alice, got %+v", users)
+	}
+
+	if _, err := client.GetUsers(); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("expected exhausted cassette error, got %v", err)
+	}
+	if replayer.Remaining() != 0 {
+		t.Errorf("expected all interactions to be used, got %d remaining", replayer.Remaining())
+	}
+}
+
+// TestReplayer_MatchBody tests that body matching selects the right interaction
+func TestReplayer_MatchBody(t *testing.T) {
+	replayer := &Replayer{
+		Match: MatchMethodPathBody,
+		interactions: []Interaction{
+			{Request: RecordedRequest{Method: "POST", Path: "/user", Body: `{"name":"Bob"}`}, Response: RecordedResponse{Status: 201, Body: `{"id":"2","name":"Bob"}`}},
+			{Request: RecordedRequest{Method: "POST", Path: "/user", Body: `{"name":"Alice"}`}, Response: RecordedResponse{Status: 201, Body: `{"id":"1","name":"Alice"}`}},
+		},
+		used: make([]bool, 2),
+	}
+
+	req, _ := http.NewRequest("POST", "http://example.com/user", strings.NewReader(`{"name":"Alice"}`))
+	res, err := replayer.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated || replayer.used[0] || !replayer.used[1] {
+		t.Errorf("expected the Alice interaction to be replayed, got status %d and used %v", res.StatusCode, replayer.used)
+	}
+}
+
+// TestRecorder_RedactsCredentials tests that credential headers are not written to the cassette
+func TestRecorder_RedactsCredentials(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected the real Authorization header to be sent, got %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	recorder, err := NewRecorder(cassette, nil)
+	if err != nil {
+		t.Fatalf("expected no error creating recorder, got %v", err)
+	}
+	req, _ := http.NewRequest("GET", server.URL+"/user", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	res, err := (&http.Client{Transport: recorder}).Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	res.Body.Close()
+	if res.Header.Get("Set-Cookie") != "session=secret" {
+		t.Errorf("expected the caller to get the real Set-Cookie header, got %q", res.Header.Get("Set-Cookie"))
+	}
+	recorder.Close()
+
+	data, _ := os.ReadFile(cassette)
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("expected credentials to be redacted, got %s", data)
+	}
+	interactions, err := LoadCassette(cassette)
+	if err != nil || len(interactions) != 1 {
+		t.Fatalf("expected one interaction, got %d (%v)", len(interactions), err)
+	}
+	if got := interactions[0].Request.Header.Get("Authorization"); got != "REDACTED" {
+		t.Errorf("expected a redacted Authorization header, got %q", got)
+	}
+	if got := interactions[0].Response.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected other headers to be kept, got %q", got)
+	}
+}
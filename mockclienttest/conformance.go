@@ -0,0 +1,215 @@
+package mockclienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// ConformanceResult - The outcome of one checked behavior
+type ConformanceResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ConformanceReport - The outcome of every behavior checked against a backend
+type ConformanceReport struct {
+	BaseURL string              `json:"baseUrl"`
+	Results []ConformanceResult `json:"results"`
+}
+
+// Passed - Reports whether every behavior passed
+func (r *ConformanceReport) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String - Formats the report as one PASS/FAIL line per behavior
+func (r *ConformanceReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "conformance of %s\n", r.BaseURL)
+	for _, result := range r.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&sb, "%s %s", status, result.Name)
+		if result.Detail != "" {
+			fmt.Fprintf(&sb, ": %s", result.Detail)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// conformanceResource - A resource under test with a record to create and a field to patch
+type conformanceResource struct {
+	path   string
+	create map[string]interface{}
+	field  string
+	value  string
+	// fetch reads the record back through mockclient.Client
+	fetch func(client *mockclient.Client, id int) error
+}
+
+// RunConformance - Exercises create/get/list/update/delete for users and products against baseURL
+// and checks the wire behavior this client relies on. Records it creates are deleted again.
+func RunConformance(baseURL string) *ConformanceReport {
+	c := &conformance{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 10 * time.Second},
+		report:  &ConformanceReport{BaseURL: baseURL, Results: []ConformanceResult{}},
+	}
+
+	c.run(conformanceResource{
+		path:   "/user",
+		create: map[string]interface{}{"name": "Conformance", "lastName": "Check", "address": "1 Test St", "favoriteDogBreed": "Beagle"},
+		field:  "address",
+		value:  "2 Test St",
+		fetch: func(client *mockclient.Client, id int) error {
+			_, err := client.GetUserByID(id)
+			return err
+		},
+	})
+	c.run(conformanceResource{
+		path:   "/products",
+		create: map[string]interface{}{"name": "Conformance Check", "price": "9.990000", "stock": "5", "type": "Test", "department": "QA"},
+		field:  "stock",
+		value:  "4",
+		fetch: func(client *mockclient.Client, id int) error {
+			_, err := client.GetProductByID(id)
+			return err
+		},
+	})
+
+	return c.report
+}
+
+// conformance - State of a conformance run
+type conformance struct {
+	baseURL string
+	http    *http.Client
+	report  *ConformanceReport
+}
+
+// check - Records the outcome of a behavior
+func (c *conformance) check(name string, passed bool, format string, args ...interface{}) bool {
+	result := ConformanceResult{Name: name, Passed: passed}
+	if !passed {
+		result.Detail = fmt.Sprintf(format, args...)
+	}
+	c.report.Results = append(c.report.Results, result)
+	return passed
+}
+
+// run - Checks every behavior for one resource; later checks are skipped when creation fails
+func (c *conformance) run(res conformanceResource) {
+	name := strings.TrimPrefix(res.path, "/")
+
+	status, created, err := c.do(http.MethodPost, res.path, res.create)
+	if !c.check(name+": create returns 201", err == nil && status == http.StatusCreated, "status %d, error %v", status, err) {
+		return
+	}
+
+	id, isString := created["id"].(string)
+	c.check(name+": id is encoded as a string", isString && id != "", "got %#v", created["id"])
+	if id == "" {
+		id = fmt.Sprint(created["id"])
+	}
+	_, stamped := created["createdAt"].(string)
+	c.check(name+": create stamps createdAt", stamped, "got %#v", created["createdAt"])
+
+	status, fetched, err := c.do(http.MethodGet, res.path+"/"+id, nil)
+	c.check(name+": get by id returns 200 and the record", err == nil && status == http.StatusOK && fetched[res.field] == res.create[res.field],
+		"status %d, %s %#v, error %v", status, res.field, fetched[res.field], err)
+
+	client, _ := mockclient.NewClient(&c.baseURL)
+	client.HTTPClient = c.http
+	numericID, _ := strconv.Atoi(id)
+	err = res.fetch(client, numericID)
+	c.check(name+": client decodes the record", err == nil, "error %v", err)
+
+	status, body, err := c.raw(http.MethodGet, res.path, nil)
+	listed := []map[string]interface{}{}
+	found := false
+	if err == nil && json.Unmarshal(body, &listed) == nil {
+		for _, record := range listed {
+			found = found || fmt.Sprint(record["id"]) == id
+		}
+	}
+	c.check(name+": list returns 200 and includes the record", status == http.StatusOK && found, "status %d, found %v, error %v", status, found, err)
+
+	patch := map[string]interface{}{res.field: res.value}
+	status, patched, err := c.do(http.MethodPatch, res.path+"/"+id, patch)
+	c.check(name+": patch returns 200 and the new value", err == nil && status == http.StatusOK && patched[res.field] == res.value,
+		"status %d, %s %#v, error %v", status, res.field, patched[res.field], err)
+
+	kept := true
+	for k, v := range res.create {
+		if k != res.field && patched[k] != v {
+			kept = false
+		}
+	}
+	c.check(name+": patch keeps fields it does not mention", err == nil && kept, "got %v", patched)
+
+	status, _, err = c.raw(http.MethodDelete, res.path+"/"+id, nil)
+	c.check(name+": delete returns 200 or 204", err == nil && (status == http.StatusOK || status == http.StatusNoContent), "status %d, error %v", status, err)
+
+	status, _, err = c.raw(http.MethodGet, res.path+"/"+id, nil)
+	c.check(name+": get after delete returns 404", err == nil && status == http.StatusNotFound, "status %d, error %v", status, err)
+}
+
+// do - Sends a JSON request and decodes a JSON object response
+func (c *conformance) do(method, path string, payload interface{}) (int, map[string]interface{}, error) {
+	status, body, err := c.raw(method, path, payload)
+	if err != nil {
+		return status, nil, err
+	}
+
+	record := map[string]interface{}{}
+	if err := json.Unmarshal(body, &record); err != nil {
+		return status, nil, fmt.Errorf("invalid JSON object %q: %v", body, err)
+	}
+	return status, record, nil
+}
+
+// raw - Sends a request and returns the status and body
+func (c *conformance) raw(method, path string, payload interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	return res.StatusCode, body, err
+}
@@ -0,0 +1,56 @@
+// conformance_test.go
+
+package mockclienttest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRunConformance_FakeServer tests that the fake server conforms
+func TestRunConformance_FakeServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	report := RunConformance(server.URL)
+	if !report.Passed() {
+		t.Fatalf("expected the fake server to conform, got:\n%s", report)
+	}
+	if len(report.Results) != 20 {
+		t.Errorf("expected 20 checked behaviors, got %d", len(report.Results))
+	}
+	if len(server.Users()) != 0 || len(server.Products()) != 0 {
+		t.Errorf("expected conformance records to be cleaned up")
+	}
+}
+
+// TestRunConformance_NumericIDs tests that a backend sending numeric IDs fails the relevant checks
+func TestRunConformance_NumericIDs(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	// Proxy the fake server, rewriting string IDs as numbers
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transport := NewFaultTransport(nil, 1, &FaultRule{Probability: 1, Fault: Fault{NumericIDs: true}})
+		out, _ := http.NewRequest(r.Method, server.URL+r.URL.Path, r.Body)
+		res, err := transport.RoundTrip(out)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer res.Body.Close()
+		w.WriteHeader(res.StatusCode)
+		io.Copy(w, res.Body)
+	}))
+	defer proxy.Close()
+
+	report := RunConformance(proxy.URL)
+	if report.Passed() {
+		t.Fatalf("expected numeric IDs to fail conformance")
+	}
+	if !strings.Contains(report.String(), "FAIL user: id is encoded as a string") {
+		t.Errorf("expected id encoding failure in report, got:\n%s", report)
+	}
+}
@@ -0,0 +1,161 @@
+package mockclienttest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault - What to do to a request; the zero Fault passes the request through untouched
+type Fault struct {
+	// Latency delays the request before anything else happens
+	Latency time.Duration
+	// Reset fails the request with a connection reset error
+	Reset bool
+	// Status answers with this status and Body instead of forwarding the request
+	Status int
+	Body   string
+	// TruncateBody keeps only the first TruncateBody bytes of the real response body
+	TruncateBody int
+	// NumericIDs rewrites string IDs ("id": "1") in the real response as numbers ("id": 1)
+	NumericIDs bool
+}
+
+// FaultRule - Injects faults into requests accepted by Match.
+// When Sequence is set, the nth matching request gets Sequence[n] and later ones pass through;
+// otherwise each matching request gets Fault with the given Probability.
+type FaultRule struct {
+	Match       func(*http.Request) bool
+	Probability float64
+	Fault       Fault
+	Sequence    []Fault
+
+	calls int
+}
+
+// FaultTransport - A RoundTripper that injects faults before or after forwarding to Next
+type FaultTransport struct {
+	Next  http.RoundTripper
+	Rules []*FaultRule
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	injected int
+}
+
+// NewFaultTransport - Creates a transport whose random choices are reproducible for a given seed.
+// A nil next uses http.DefaultTransport.
+func NewFaultTransport(next http.RoundTripper, seed int64, rules ...*FaultRule) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &FaultTransport{Next: next, Rules: rules, rng: rand.New(rand.NewSource(seed))}
+}
+
+// MatchRoute - Matches requests with the given method (empty for any) and path prefix
+func MatchRoute(method, pathPrefix string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		return (method == "" || req.Method == method) && strings.HasPrefix(req.URL.Path, pathPrefix)
+	}
+}
+
+// Injected - Returns how many requests received a fault
+func (ft *FaultTransport) Injected() int {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return ft.injected
+}
+
+// RoundTrip - Applies the first rule that matches and picks a fault, then forwards if needed
+func (ft *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := ft.pick(req)
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if fault.Reset {
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	if fault.Status != 0 {
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", fault.Status, http.StatusText(fault.Status)),
+			StatusCode:    fault.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(fault.Body)),
+			ContentLength: int64(len(fault.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	res, err := ft.Next.RoundTrip(req)
+	if err != nil || (fault.TruncateBody == 0 && !fault.NumericIDs) {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if fault.NumericIDs {
+		body = numericID.ReplaceAll(body, []byte(`"id":$1`))
+	}
+	if fault.TruncateBody > 0 && fault.TruncateBody < len(body) {
+		body = body[:fault.TruncateBody]
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Del("Content-Length")
+
+	return res, nil
+}
+
+// numericID - Finds string-encoded IDs in a JSON body
+var numericID = regexp.MustCompile(`"id"\s*:\s*"(-?\d+)"`)
+
+// pick - Chooses the fault for a request
+func (ft *FaultTransport) pick(req *http.Request) Fault {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.rng == nil {
+		ft.rng = rand.New(rand.NewSource(1))
+	}
+
+	for _, rule := range ft.Rules {
+		if rule.Match != nil && !rule.Match(req) {
+			continue
+		}
+
+		var fault Fault
+		if rule.Sequence != nil {
+			if rule.calls < len(rule.Sequence) {
+				fault = rule.Sequence[rule.calls]
+			}
+			rule.calls++
+		} else if ft.rng.Float64() < rule.Probability {
+			fault = rule.Fault
+		}
+
+		if fault != (Fault{}) {
+			ft.injected++
+		}
+		return fault
+	}
+
+	return Fault{}
+}
@@ -0,0 +1,83 @@
+// faults_test.go
+
+package mockclienttest
+
+import (
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// newFaultyClient returns a client for server whose requests go through the given rules
+func newFaultyClient(server *Server, seed int64, rules ...*FaultRule) (*mockclient.Client, *FaultTransport) {
+	transport := NewFaultTransport(nil, seed, rules...)
+	client := server.NewClient()
+	client.HTTPClient = &http.Client{Transport: transport}
+	return client, transport
+}
+
+// TestFaultTransport_Sequence tests deterministic per-request faults
+func TestFaultTransport_Sequence(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SeedProducts(mockclient.Product{ID: 3, Name: "Ball", Price: 5, Stock: 10})
+
+	client, transport := newFaultyClient(server, 1, &FaultRule{
+		Match:    MatchRoute(http.MethodGet, "/products/3"),
+		Sequence: []Fault{{Status: http.StatusServiceUnavailable}, {Reset: true}, {NumericIDs: true}, {TruncateBody: 10}},
+	})
+
+	_, err := client.GetProductByID(3)
+	var statusErr *mockclient.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected injected 503, got %v", err)
+	}
+	if _, err := client.GetProductByID(3); !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("expected connection reset, got %v", err)
+	}
+	if _, err := client.GetProductByID(3); err == nil {
+		t.Errorf("expected numeric id to fail decoding, got nil")
+	}
+	if _, err := client.GetProductByID(3); err == nil {
+		t.Errorf("expected truncated body to fail decoding, got nil")
+	}
+	if _, err := client.GetProductByID(3); err != nil {
+		t.Errorf("expected requests after the sequence to pass through, got %v", err)
+	}
+	if transport.Injected() != 4 {
+		t.Errorf("expected 4 injected faults, got %d", transport.Injected())
+	}
+}
+
+// TestFaultTransport_SeededProbability tests that the same seed injects the same faults
+func TestFaultTransport_SeededProbability(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	outcomes := func(seed int64) []bool {
+		client, _ := newFaultyClient(server, seed, &FaultRule{Probability: 0.5, Fault: Fault{Status: http.StatusInternalServerError}})
+		var failed []bool
+		for i := 0; i < 20; i++ {
+			_, err := client.GetUsers()
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+
+	first, second := outcomes(42), outcomes(42)
+	injected := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected identical outcomes for the same seed, got %v and %v", first, second)
+		}
+		if first[i] {
+			injected++
+		}
+	}
+	if injected == 0 || injected == len(first) {
+		t.Errorf("expected some but not all requests to fail, got %d of %d", injected, len(first))
+	}
+}
@@ -0,0 +1,203 @@
+package mockclienttest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// Fixtures - Records created from fixture files, keyed by fixture name
+type Fixtures struct {
+	Users    map[string]mockclient.User
+	Products map[string]mockclient.Product
+}
+
+// UserID - Returns the server-assigned ID of the named user fixture
+func (f *Fixtures) UserID(name string) int {
+	return f.Users[name].ID
+}
+
+// ProductID - Returns the server-assigned ID of the named product fixture
+func (f *Fixtures) ProductID(name string) int {
+	return f.Products[name].ID
+}
+
+// LoadFixtures - Creates the users and products described in JSON or YAML fixture files and
+// deletes them again when the test finishes, whether it passed or not. Files map fixture names
+// to records under "users" and "products":
+//
+//	users:
+//	  alice:
+//	    name: Alice
+//	    lastName: Smith
+//	products:
+//	  ball:
+//	    name: Ball
+//	    price: 5.00
+//	    stock: 10
+//
+// Records are created in file order, and by fixture name within a file. The deletes run as t.Cleanup
+// functions, after the test's deferred calls, so a server started by the test must still be running
+// then: start it with NewTestServer, or close it with t.Cleanup rather than defer.
+func LoadFixtures(t testing.TB, client mockclient.API, paths ...string) *Fixtures {
+	t.Helper()
+
+	fixtures := &Fixtures{Users: map[string]mockclient.User{}, Products: map[string]mockclient.Product{}}
+	for _, path := range paths {
+		doc, err := readFixtureFile(path)
+		if err != nil {
+			t.Fatalf("mockclienttest: %s: %v", path, err)
+		}
+
+		for _, fixture := range doc.users {
+			name := fixture.name
+			if _, dup := fixtures.Users[name]; dup {
+				t.Fatalf("mockclienttest: %s: duplicate user fixture %q", path, name)
+			}
+			user := mockclient.User{}
+			if err := decodeFixture(fixture.fields, &user); err != nil {
+				t.Fatalf("mockclienttest: %s: user %q: %v", path, name, err)
+			}
+			created, err := client.CreateUser(&user)
+			if err != nil {
+				t.Fatalf("mockclienttest: creating user %q: %v", name, err)
+			}
+			fixtures.Users[name] = *created
+			registerCleanup(t, "user "+name, func() error { return client.DeleteUser(created.ID) })
+		}
+
+		for _, fixture := range doc.products {
+			name := fixture.name
+			if _, dup := fixtures.Products[name]; dup {
+				t.Fatalf("mockclienttest: %s: duplicate product fixture %q", path, name)
+			}
+			product := mockclient.Product{}
+			if err := decodeFixture(fixture.fields, &product); err != nil {
+				t.Fatalf("mockclienttest: %s: product %q: %v", path, name, err)
+			}
+			created, err := client.CreateProduct(&product)
+			if err != nil {
+				t.Fatalf("mockclienttest: creating product %q: %v", name, err)
+			}
+			fixtures.Products[name] = *created
+			registerCleanup(t, "product "+name, func() error { return client.DeleteProduct(created.ID) })
+		}
+	}
+
+	return fixtures
+}
+
+// registerCleanup - Deletes a created record at the end of the test; cleanups run in reverse order.
+// Records the test already deleted itself are not an error.
+func registerCleanup(t testing.TB, what string, del func() error) {
+	t.Cleanup(func() {
+		var statusErr *mockclient.StatusError
+		if err := del(); err != nil && !(errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound) {
+			t.Errorf("mockclienttest: cleaning up %s: %v", what, err)
+		}
+	})
+}
+
+// fixtureDoc - The parsed contents of a fixture file, each section sorted by fixture name
+type fixtureDoc struct {
+	users    []namedFixture
+	products []namedFixture
+}
+
+// namedFixture - The fields of one fixture record
+type namedFixture struct {
+	name   string
+	fields map[string]interface{}
+}
+
+// readFixtureFile - Parses a .json, .yaml or .yml fixture file
+func readFixtureFile(path string) (*fixtureDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &root)
+	case ".yaml", ".yml":
+		root, err = parseYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &fixtureDoc{}
+	for key := range root {
+		if key != "users" && key != "products" {
+			return nil, fmt.Errorf("unknown section %q", key)
+		}
+	}
+	if doc.users, err = namedFixtures(root["users"], "users"); err != nil {
+		return nil, err
+	}
+	if doc.products, err = namedFixtures(root["products"], "products"); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// namedFixtures - Converts a section mapping names to records into a sorted list
+func namedFixtures(section interface{}, label string) ([]namedFixture, error) {
+	if section == nil || section == "" {
+		return nil, nil
+	}
+	records, ok := section.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must map fixture names to records", label)
+	}
+
+	out := []namedFixture{}
+	for name, record := range records {
+		fields, ok := record.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s.%s must be a record", label, name)
+		}
+		out = append(out, namedFixture{name: name, fields: fields})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out, nil
+}
+
+// decodeFixture - Converts fixture fields, which may use JSON numbers, into a record in the API wire format
+func decodeFixture(fields map[string]interface{}, out interface{}) error {
+	wire := map[string]interface{}{"id": "0"}
+	if _, ok := out.(*mockclient.Product); ok {
+		wire["price"], wire["stock"] = "0", "0"
+	}
+	for k, v := range fields {
+		switch v := v.(type) {
+		case string:
+			wire[k] = v
+		case float64:
+			wire[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			wire[k] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("field %q must be a scalar", k)
+		}
+	}
+
+	data, err := json.Marshal(wire)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
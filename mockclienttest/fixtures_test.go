@@ -0,0 +1,58 @@
+// fixtures_test.go
+
+package mockclienttest
+
+import (
+	"testing"
+)
+
+// TestLoadFixtures tests fixture creation and cleanup at the end of the test
+func TestLoadFixtures(t *testing.T) {
+	var server *Server
+	t.Run("load", func(t *testing.T) {
+		server = NewTestServer(t)
+		client := server.NewClient()
+		fixtures := LoadFixtures(t, client, "testdata/fixtures.yaml", "testdata/fixtures.json")
+
+		if fixtures.UserID("alice") != 1 || fixtures.UserID("bob") != 2 {
+			t.Errorf("expected alice and bob to get IDs 1 and 2, got %d and %d", fixtures.UserID("alice"), fixtures.UserID("bob"))
+		}
+		if fixtures.Users["alice"].FavoriteDogBreed != "Border Collie" || fixtures.Users["bob"].LastName != "O'Brien" {
+			t.Errorf("unexpected users: %+v", fixtures.Users)
+		}
+		if kite := fixtures.Products["kite"]; kite.Price != 12.5 || kite.Stock != 4 {
+			t.Errorf("unexpected kite product: %+v", kite)
+		}
+		if fixtures.ProductID("ball") == 0 || fixtures.Products["ball"].Stock != 10 {
+			t.Errorf("unexpected ball product: %+v", fixtures.Products["ball"])
+		}
+
+		// Deleting a fixture in the test must not make cleanup fail
+		client.DeleteUser(fixtures.UserID("bob"))
+	})
+
+	if users := server.Users(); len(users) != 0 {
+		t.Errorf("expected users to be cleaned up, got %+v", users)
+	}
+	if products := server.Products(); len(products) != 0 {
+		t.Errorf("expected products to be cleaned up, got %+v", products)
+	}
+}
+
+// TestParseYAML tests the supported YAML subset
+func TestParseYAML(t *testing.T) {
+	doc, err := parseYAML([]byte("a:\n  b: 'it''s'\n  c: \"x # y\" # comment\nd: plain value\ne: O'Brien  # irish\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	nested, _ := doc["a"].(map[string]interface{})
+	if nested["b"] != "it's" || nested["c"] != "x # y" || doc["d"] != "plain value" || doc["e"] != "O'Brien" {
+		t.Errorf("unexpected document: %v", doc)
+	}
+
+	for _, bad := range []string{"a:\n  - 1\n", "a: 1\n   b: 2\n", "a: [1, 2]\n", "a:b\n"} {
+		if _, err := parseYAML([]byte(bad)); err == nil {
+			t.Errorf("expected an error parsing %q", bad)
+		}
+	}
+}
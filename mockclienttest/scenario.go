@@ -0,0 +1,205 @@
+package mockclienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// Scenario - A fake server that answers each route with a scripted sequence of responses.
+// Unscripted requests get a 500 and fail the test; Verify reports scripted responses never requested.
+type Scenario struct {
+	URL string
+
+	t        testing.TB
+	srv      *httptest.Server
+	mu       sync.Mutex
+	routes   []*Route
+	failures []string
+	verified bool
+}
+
+// Route - The scripted responses for one method and path, served in order
+type Route struct {
+	method string
+	path   string
+	steps  []*step
+	calls  int
+}
+
+// step - One scripted response and what the request for it must look like
+type step struct {
+	status     int
+	body       string
+	header     http.Header
+	expectBody interface{}
+	hasExpect  bool
+}
+
+// NewScenario - Starts a scripted server that is closed and verified when the test finishes
+func NewScenario(t testing.TB) *Scenario {
+	s := &Scenario{t: t}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	s.URL = s.srv.URL
+	t.Cleanup(func() {
+		s.srv.Close()
+		s.Verify()
+	})
+	return s
+}
+
+// NewClient - Returns a mockclient.Client pointed at the scenario
+func (s *Scenario) NewClient() *mockclient.Client {
+	client, _ := mockclient.NewClient(&s.URL)
+	return client
+}
+
+// On - Returns the route for method and path, creating it on first use
+func (s *Scenario) On(method, path string) *Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.routes {
+		if r.method == method && r.path == path {
+			return r
+		}
+	}
+	r := &Route{method: method, path: path}
+	s.routes = append(s.routes, r)
+	return r
+}
+
+// Respond - Adds a response with status and body to the end of the route's sequence
+func (r *Route) Respond(status int, body string) *Route {
+	r.steps = append(r.steps, &step{status: status, body: body, header: http.Header{"Content-Type": {"application/json"}}})
+	return r
+}
+
+// RespondRecord - Adds a response whose body is record encoded with its custom marshaler
+func (r *Route) RespondRecord(status int, record interface{}) *Route {
+	data, err := json.Marshal(record)
+	if err != nil {
+		panic(fmt.Sprintf("mockclienttest: cannot encode response record: %v", err))
+	}
+	return r.Respond(status, string(data))
+}
+
+// WithHeader - Sets a response header on the most recently added response
+func (r *Route) WithHeader(key, value string) *Route {
+	r.last().header.Set(key, value)
+	return r
+}
+
+// ExpectJSON - Requires the request for the most recently added response to carry an equivalent JSON body
+func (r *Route) ExpectJSON(body string) *Route {
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		panic(fmt.Sprintf("mockclienttest: invalid expected JSON %q: %v", body, err))
+	}
+	last := r.last()
+	last.expectBody, last.hasExpect = v, true
+	return r
+}
+
+// last - The most recently added step
+func (r *Route) last() *step {
+	if len(r.steps) == 0 {
+		panic("mockclienttest: add a response before configuring it")
+	}
+	return r.steps[len(r.steps)-1]
+}
+
+// Verify - Fails the test if any scripted response was never requested or any request was unexpected.
+// It is called automatically when the test finishes and only reports once.
+func (s *Scenario) Verify() {
+	s.t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.verified {
+		return
+	}
+	s.verified = true
+
+	satisfied := len(s.failures) == 0
+	var sb strings.Builder
+	for _, r := range s.routes {
+		fmt.Fprintf(&sb, "%s %s\n", r.method, r.path)
+		for i, st := range r.steps {
+			mark, note := "✓", ""
+			if i >= r.calls {
+				mark, note, satisfied = "✗", " (not called)", false
+			}
+			fmt.Fprintf(&sb, "  %s #%d %d%s\n", mark, i+1, st.status, note)
+		}
+		if r.calls > len(r.steps) {
+			fmt.Fprintf(&sb, "  ✗ %d extra call(s)\n", r.calls-len(r.steps))
+		}
+	}
+	if len(s.failures) > 0 {
+		sb.WriteString("problems:\n")
+		for _, f := range s.failures {
+			fmt.Fprintf(&sb, "  %s\n", strings.ReplaceAll(f, "\n", "\n  "))
+		}
+	}
+
+	if !satisfied {
+		s.t.Errorf("mockclienttest: scenario not satisfied:\n%s", sb.String())
+	}
+}
+
+// serve - Answers a request with the next scripted response for its route
+func (s *Scenario) serve(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var route *Route
+	for _, r := range s.routes {
+		if r.method == req.Method && r.path == req.URL.Path {
+			route = r
+		}
+	}
+	if route == nil {
+		s.fail(w, "unexpected request %s %s", req.Method, req.URL.Path)
+		return
+	}
+
+	route.calls++
+	if route.calls > len(route.steps) {
+		s.fail(w, "%s %s called %d times, only %d response(s) scripted", req.Method, req.URL.Path, route.calls, len(route.steps))
+		return
+	}
+
+	st := route.steps[route.calls-1]
+	if st.hasExpect {
+		var actual interface{}
+		if err := json.Unmarshal(body, &actual); err != nil || !reflect.DeepEqual(actual, st.expectBody) {
+			expected, _ := json.Marshal(st.expectBody)
+			s.failures = append(s.failures, fmt.Sprintf("%s %s #%d: body mismatch\n- expected: %s\n+ actual:   %s",
+				req.Method, req.URL.Path, route.calls, expected, bytes.TrimSpace(body)))
+		}
+	}
+
+	for k, v := range st.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(st.status)
+	io.WriteString(w, st.body)
+}
+
+// fail - Records a problem and answers with a 500
+func (s *Scenario) fail(w http.ResponseWriter, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	s.failures = append(s.failures, msg)
+	http.Error(w, msg, http.StatusInternalServerError)
+}
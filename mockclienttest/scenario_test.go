@@ -0,0 +1,85 @@
+// scenario_test.go
+
+package mockclienttest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// fakeTB captures failures and cleanups so scenario verification itself can be tested
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper()           {}
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// finish runs the registered cleanups like the testing package does
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+// TestScenario_OrderedResponses tests a route answering 503, then 200 with stock 0, then 404
+func TestScenario_OrderedResponses(t *testing.T) {
+	scenario := NewScenario(t)
+	scenario.On(http.MethodGet, "/products/3").
+		Respond(http.StatusServiceUnavailable, `"try later"`).
+		RespondRecord(http.StatusOK, mockclient.Product{ID: 3, Name: "Ball", Price: 5, Stock: 0}).
+		Respond(http.StatusNotFound, `"Not found"`)
+	client := scenario.NewClient()
+
+	var statusErr *mockclient.StatusError
+	if _, err := client.GetProductByID(3); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 first, got %v", err)
+	}
+	product, err := client.GetProductByID(3)
+	if err != nil || product.Stock != 0 || product.Name != "Ball" {
+		t.Errorf("expected Ball with stock 0 second, got %+v and %v", product, err)
+	}
+	if _, err := client.GetProductByID(3); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 third, got %v", err)
+	}
+}
+
+// TestScenario_VerifyReportsMissingAndUnexpectedCalls tests the verification diff
+func TestScenario_VerifyReportsMissingAndUnexpectedCalls(t *testing.T) {
+	tb := &fakeTB{}
+	scenario := NewScenario(tb)
+	scenario.On(http.MethodPost, "/user").
+		Respond(http.StatusCreated, `{"id": "1", "name": "Alice"}`).
+		ExpectJSON(`{"id": "0", "name": "Alice"}`)
+	scenario.On(http.MethodGet, "/user/1").Respond(http.StatusOK, `{"id": "1"}`)
+	client := scenario.NewClient()
+
+	client.CreateUser(&mockclient.User{Name: "Bob"})
+	client.DeleteUser(1)
+	tb.finish()
+
+	if len(tb.errors) != 1 {
+		t.Fatalf("expected one verification failure, got %v", tb.errors)
+	}
+	report := tb.errors[0]
+	for _, want := range []string{
+		"✗ #1 200 (not called)",
+		"unexpected request DELETE /user/1",
+		`- expected: {"id":"0","name":"Alice"}`,
+		`+ actual:   {"id":"0","name":"Bob"}`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
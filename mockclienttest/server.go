@@ -0,0 +1,278 @@
+// Package mockclienttest provides an in-memory fake of the mock API for testing code that uses mockclient.
+package mockclienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// Server - A stateful fake of the /user and /products endpoints.
+// Records are stored in the API wire format: string IDs assigned by auto-increment and a createdAt stamp.
+type Server struct {
+	URL string
+
+	// Now stamps createdAt on new records; defaults to time.Now
+	Now func() time.Time
+
+	srv         *httptest.Server
+	mu          sync.Mutex
+	collections map[string]*collection
+}
+
+// collection - The records of one resource, keyed by numeric ID
+type collection struct {
+	nextID  int
+	records map[int]map[string]interface{}
+}
+
+// NewServer - Starts an empty fake server; call Close when done, or use NewTestServer
+func NewServer() *Server {
+	s := &Server{
+		Now: time.Now,
+		collections: map[string]*collection{
+			"user":     {nextID: 1, records: map[int]map[string]interface{}{}},
+			"products": {nextID: 1, records: map[int]map[string]interface{}{}},
+		},
+	}
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// NewTestServer - Starts an empty fake server that is closed when t finishes, after the cleanups
+// registered later in the test, such as the fixture deletes of LoadFixtures
+func NewTestServer(t testing.TB) *Server {
+	s := NewServer()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close - Shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewClient - Returns a mockclient.Client pointed at the server
+func (s *Server) NewClient() *mockclient.Client {
+	client, _ := mockclient.NewClient(&s.URL)
+	return client
+}
+
+// SeedUsers - Stores users as-is; users without an ID are assigned the next one
+func (s *Server) SeedUsers(users ...mockclient.User) {
+	for _, u := range users {
+		s.seed("user", u)
+	}
+}
+
+// SeedProducts - Stores products as-is; products without an ID are assigned the next one
+func (s *Server) SeedProducts(products ...mockclient.Product) {
+	for _, p := range products {
+		s.seed("products", p)
+	}
+}
+
+// Users - Returns the stored users ordered by ID
+func (s *Server) Users() []mockclient.User {
+	users := []mockclient.User{}
+	for _, data := range s.dump("user") {
+		u := mockclient.User{}
+		if err := json.Unmarshal(data, &u); err == nil {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+// Products - Returns the stored products ordered by ID
+func (s *Server) Products() []mockclient.Product {
+	products := []mockclient.Product{}
+	for _, data := range s.dump("products") {
+		p := mockclient.Product{}
+		if err := json.Unmarshal(data, &p); err == nil {
+			products = append(products, p)
+		}
+	}
+	return products
+}
+
+// Reset - Removes every record and restarts IDs at 1
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.collections {
+		c.nextID = 1
+		c.records = map[int]map[string]interface{}{}
+	}
+}
+
+// ServeHTTP - Implements the CRUD semantics of the mock API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[parts[0]]
+	if !ok || len(parts) > 2 {
+		writeJSON(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, paginate(c.list(), r.URL.Query()))
+		case http.MethodPost:
+			fields, err := decodeFields(r)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			id := c.nextID
+			fields["id"] = strconv.Itoa(id)
+			if _, ok := fields["createdAt"]; !ok {
+				fields["createdAt"] = s.Now().UTC().Format(time.RFC3339Nano)
+			}
+			c.records[id] = fields
+			c.nextID++
+			writeJSON(w, http.StatusCreated, fields)
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	record, found := c.records[id]
+	if err != nil || !found {
+		writeJSON(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, record)
+	case http.MethodPut, http.MethodPatch:
+		fields, err := decodeFields(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for k, v := range fields {
+			if k != "id" {
+				record[k] = v
+			}
+		}
+		writeJSON(w, http.StatusOK, record)
+	case http.MethodDelete:
+		delete(c.records, id)
+		writeJSON(w, http.StatusOK, record)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// seed - Stores a record in its wire format, assigning an ID when it has none
+func (s *Server) seed(resource string, record interface{}) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		panic(fmt.Sprintf("mockclienttest: cannot encode seed record: %v", err))
+	}
+	fields := map[string]interface{}{}
+	json.Unmarshal(data, &fields)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collections[resource]
+	id, _ := strconv.Atoi(fmt.Sprint(fields["id"]))
+	if id == 0 {
+		id = c.nextID
+		fields["id"] = strconv.Itoa(id)
+	}
+	if _, ok := fields["createdAt"]; !ok {
+		fields["createdAt"] = s.Now().UTC().Format(time.RFC3339Nano)
+	}
+	c.records[id] = fields
+	if id >= c.nextID {
+		c.nextID = id + 1
+	}
+}
+
+// dump - Returns the encoded records of a resource ordered by ID
+func (s *Server) dump(resource string) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := [][]byte{}
+	for _, record := range s.collections[resource].list() {
+		data, _ := json.Marshal(record)
+		out = append(out, data)
+	}
+	return out
+}
+
+// list - Returns the records ordered by ID
+func (c *collection) list() []map[string]interface{} {
+	ids := make([]int, 0, len(c.records))
+	for id := range c.records {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	records := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, c.records[id])
+	}
+	return records
+}
+
+// paginate - Applies mockapi.io's page and limit query parameters (page counts from 1);
+// without a valid limit every record is returned
+func paginate(records []map[string]interface{}, query url.Values) []map[string]interface{} {
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		return records
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * limit
+	if start >= len(records) {
+		return []map[string]interface{}{}
+	}
+	end := start + limit
+	if end > len(records) {
+		end = len(records)
+	}
+	return records[start:end]
+}
+
+// decodeFields - Reads a JSON object request body
+func decodeFields(r *http.Request) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return fields, nil
+}
+
+// writeJSON - Writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,117 @@
+// server_test.go
+
+package mockclienttest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	mockclient "github.com/carlosrv999/mockapiclient"
+)
+
+// TestServer_UserCRUD tests the real client against the fake server for users
+func TestServer_UserCRUD(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Now = func() time.Time { return time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC) }
+	client := server.NewClient()
+
+	created, err := client.CreateUser(&mockclient.User{Name: "Alice", LastName: "Smith"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.ID != 1 || created.CreatedAt != "2024-08-05T10:00:00Z" {
+		t.Errorf("expected ID 1 stamped at 2024-08-05T10:00:00Z, got ID %d at %s", created.ID, created.CreatedAt)
+	}
+
+	updated, err := client.UpdateUser(&mockclient.User{ID: 1, Address: "456 Elm St"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Name != "Alice" || updated.Address != "456 Elm St" {
+		t.Errorf("expected PATCH to merge fields, got %+v", updated)
+	}
+
+	if err := client.DeleteUser(1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err = client.GetUserByID(1)
+	var statusErr *mockclient.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %v", err)
+	}
+}
+
+// TestServer_SeedProducts tests seeded data and auto-increment after seeding
+func TestServer_SeedProducts(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SeedProducts(
+		mockclient.Product{ID: 5, Name: "Ball", Price: 5, Stock: 10},
+		mockclient.Product{Name: "Kite", Price: 12, Stock: 4},
+	)
+	client := server.NewClient()
+
+	products, err := client.GetProducts()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(products) != 2 || products[0].ID != 5 || products[1].ID != 6 {
+		t.Fatalf("expected products 5 and 6, got %+v", products)
+	}
+
+	created, err := client.CreateProduct(&mockclient.Product{Name: "Yo-yo", Price: 2, Stock: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.ID != 7 {
+		t.Errorf("expected next ID 7, got %d", created.ID)
+	}
+	if got := server.Products(); len(got) != 3 || got[2].Name != "Yo-yo" {
+		t.Errorf("expected stored Yo-yo, got %+v", got)
+	}
+}
+
+// TestServer_UnknownRoute tests 404s for unknown paths
+func TestServer_UnknownRoute(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/orders")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", res.StatusCode)
+	}
+}
+
+// TestServer_Paging tests the page and limit query parameters with the client's exporter
+func TestServer_Paging(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SeedUsers(
+		mockclient.User{Name: "Alice"},
+		mockclient.User{Name: "Bob"},
+		mockclient.User{Name: "Carol"},
+	)
+
+	var buf strings.Builder
+	var pages int
+	_, err := server.NewClient().ExportUsers(&buf, mockclient.ExportOptions{
+		Format:     mockclient.FormatCSV,
+		Columns:    []string{"name"},
+		PageSize:   2,
+		Checkpoint: func(mockclient.ExportCheckpoint) error { pages++; return nil },
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if buf.String() != "name\nAlice\nBob\nCarol\n" || pages != 2 {
+		t.Errorf("expected every user over 2 pages, got %d pages:\n%s", pages, buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+package mockclienttest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine - A significant line of a YAML document
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// parseYAML - Parses the block-mapping subset of YAML used by fixture files:
+// nested "key: value" mappings, plain or quoted scalars and comments. Scalars are returned as strings.
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	lines := []yamlLine{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := stripYAMLComment(raw)
+		if strings.TrimSpace(text) == "" || strings.TrimSpace(text) == "---" {
+			continue
+		}
+		if strings.Contains(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		trimmed := strings.TrimLeft(text, " ")
+		lines = append(lines, yamlLine{number: i + 1, indent: len(text) - len(trimmed), text: strings.TrimRight(trimmed, " ")})
+	}
+
+	if len(lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	m, rest, err := parseYAMLMapping(lines, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("line %d: unexpected indentation", rest[0].number)
+	}
+	return m, nil
+}
+
+// parseYAMLMapping - Parses consecutive lines at indent into a mapping, returning the unconsumed lines
+func parseYAMLMapping(lines []yamlLine, indent int) (map[string]interface{}, []yamlLine, error) {
+	m := map[string]interface{}{}
+	for len(lines) > 0 && lines[0].indent == indent {
+		line := lines[0]
+		lines = lines[1:]
+
+		if strings.HasPrefix(line.text, "- ") || line.text == "-" {
+			return nil, nil, fmt.Errorf("line %d: sequences are not supported", line.number)
+		}
+		sep := strings.Index(line.text, ":")
+		if sep <= 0 || (sep+1 < len(line.text) && line.text[sep+1] != ' ') {
+			return nil, nil, fmt.Errorf("line %d: expected \"key: value\"", line.number)
+		}
+		key, err := unquoteYAML(strings.TrimSpace(line.text[:sep]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", line.number, err)
+		}
+		if _, dup := m[key]; dup {
+			return nil, nil, fmt.Errorf("line %d: duplicate key %q", line.number, key)
+		}
+
+		value := strings.TrimSpace(line.text[sep+1:])
+		if value != "" {
+			if m[key], err = unquoteYAML(value); err != nil {
+				return nil, nil, fmt.Errorf("line %d: %v", line.number, err)
+			}
+			continue
+		}
+
+		if len(lines) == 0 || lines[0].indent <= indent {
+			m[key] = ""
+			continue
+		}
+		m[key], lines, err = parseYAMLMapping(lines, lines[0].indent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(lines) > 0 && lines[0].indent > indent {
+		return nil, nil, fmt.Errorf("line %d: unexpected indentation", lines[0].number)
+	}
+	return m, lines, nil
+}
+
+// unquoteYAML - Returns the value of a plain, single- or double-quoted scalar
+func unquoteYAML(s string) (string, error) {
+	switch {
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		return strconv.Unquote(s)
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.HasPrefix(s, "{") || strings.HasPrefix(s, "["):
+		return "", fmt.Errorf("flow collections are not supported")
+	}
+	return s, nil
+}
+
+// stripYAMLComment - Removes a comment that starts a line or follows whitespace, outside quotes.
+// A quote only opens a quoted scalar where a key or value starts, so O'Brien is plain text.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if before := strings.TrimRight(line[:i], " "); before == "" || strings.HasSuffix(before, ":") {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,393 @@
+package mockclient
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrQueued is returned by writes that were stored in the outbox instead of reaching the server
+var ErrQueued = errors.New("write queued in outbox")
+
+// Outbox - A durable JSONL file of writes waiting to be replayed
+type Outbox struct {
+	path string
+	mu   sync.Mutex
+}
+
+// OutboxEntry - A queued write request
+type OutboxEntry struct {
+	QueuedAt time.Time       `json:"queuedAt"`
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	// BaseVersion is the version of the record as last read through DiskCache or RecordCache, if any
+	BaseVersion string `json:"baseVersion,omitempty"`
+}
+
+// ConflictPolicy - What replay does when a record changed after a write was queued
+type ConflictPolicy int
+
+const (
+	// ConflictSkip drops the queued write and reports the conflict. An update or delete queued without
+	// a BaseVersion, because its record was never read through DiskCache or RecordCache, cannot be
+	// checked and is reported as a conflict too.
+	ConflictSkip ConflictPolicy = iota
+	// ConflictOverwrite applies the queued write anyway
+	ConflictOverwrite
+)
+
+// ReplayOutcome - The result of replaying one queued write
+type ReplayOutcome string
+
+const (
+	ReplayApplied  ReplayOutcome = "applied"
+	ReplayConflict ReplayOutcome = "conflict"
+	ReplayFailed   ReplayOutcome = "failed"
+	ReplayPending  ReplayOutcome = "pending"
+)
+
+// ReplayResult - What happened to a queued write during replay
+type ReplayResult struct {
+	Entry   OutboxEntry   `json:"entry"`
+	Outcome ReplayOutcome `json:"outcome"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// ReplayReport - The outcome of every queued write, in queue order
+type ReplayReport struct {
+	Results []ReplayResult `json:"results"`
+}
+
+// NewOutbox - Opens the outbox stored at path, which is created on the first queued write
+func NewOutbox(path string) *Outbox {
+	return &Outbox{path: path}
+}
+
+// Entries - Returns the queued writes in order
+func (o *Outbox) Entries() ([]OutboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.read()
+}
+
+// append - Durably adds an entry to the end of the outbox
+func (o *Outbox) append(entry OutboxEntry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(o.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// read - Parses every entry in the outbox; the caller must hold mu
+func (o *Outbox) read() ([]OutboxEntry, error) {
+	f, err := os.Open(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []OutboxEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), DefaultMaxResponseBytes)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		entry := OutboxEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", o.path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// rewrite - Replaces the outbox contents with entries; the caller must hold mu
+func (o *Outbox) rewrite(entries []OutboxEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	tmp := o.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, o.path)
+}
+
+// sendOrQueue - Sends a write, queuing it in the outbox when offline or when the server cannot be reached
+func (c *Client) sendOrQueue(req *http.Request) ([]byte, http.Header, error) {
+	var payload []byte
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if c.Offline {
+		return nil, nil, c.queue(req, payload, ErrOffline)
+	}
+
+	// A write that may have reached the server, e.g. one that timed out awaiting the response, is not
+	// queued: replaying it could apply it twice
+	body, header, err := c.send(req)
+	if unreachable(err) {
+		return nil, nil, c.queue(req, payload, err)
+	}
+
+	return body, header, err
+}
+
+// queue - Appends a write to the outbox and returns an error wrapping ErrQueued and the reason
+func (c *Client) queue(req *http.Request, payload []byte, reason error) error {
+	entry := OutboxEntry{
+		QueuedAt: time.Now().UTC(),
+		Method:   req.Method,
+		Path:     strings.TrimPrefix(req.URL.String(), c.HostURL),
+	}
+	if len(payload) > 0 {
+		entry.Body = payload
+	}
+	if req.Method != http.MethodPost {
+		if c.DiskCache != nil {
+			entry.BaseVersion = c.cachedVersion(req.URL.String())
+		}
+		if entry.BaseVersion == "" {
+			entry.BaseVersion = c.recordCacheVersion(entry.Path)
+		}
+	}
+
+	if err := c.Outbox.append(entry); err != nil {
+		return fmt.Errorf("%v; queuing failed: %w", reason, err)
+	}
+
+	return fmt.Errorf("%w: %s %s: %v", ErrQueued, entry.Method, entry.Path, reason)
+}
+
+// cachedVersion - Returns the version of the record at target as last stored in DiskCache
+func (c *Client) cachedVersion(target string) string {
+	entry, err := c.DiskCache.load(target)
+	if err != nil {
+		return ""
+	}
+	if etag := entry.Header.Get("ETag"); etag != "" {
+		return etag
+	}
+
+	record := newRecordFor(strings.TrimPrefix(target, c.HostURL))
+	if record == nil || json.Unmarshal(entry.Body, record) != nil {
+		return ""
+	}
+	version, err := contentVersion(record)
+	if err != nil {
+		return ""
+	}
+
+	return version
+}
+
+// recordCacheVersion - Returns the content version of the record at path as held by RecordCache
+func (c *Client) recordCacheVersion(path string) string {
+	var resource string
+	switch {
+	case strings.HasPrefix(path, "/user/"):
+		resource = "user"
+	case strings.HasPrefix(path, "/products/"):
+		resource = "product"
+	default:
+		return ""
+	}
+	id, err := strconv.Atoi(path[strings.LastIndex(path, "/")+1:])
+	if err != nil {
+		return ""
+	}
+
+	record, ok := c.RecordCache.get(resource, id)
+	if !ok {
+		return ""
+	}
+	version, err := contentVersion(record)
+	if err != nil {
+		return ""
+	}
+
+	return version
+}
+
+// ReplayOutbox - Sends queued writes in order. Writes whose record changed and writes the server rejects
+// with a 4xx status are reported and removed. Replay stops at the first write that cannot be resolved
+// now, because the server is unreachable, answers with a 5xx status or its conflict check fails, and
+// returns that error, leaving the write and the rest queued as pending.
+func (c *Client) ReplayOutbox(policy ConflictPolicy) (*ReplayReport, error) {
+	if c.Outbox == nil {
+		return nil, fmt.Errorf("no outbox configured")
+	}
+	if c.Offline {
+		return nil, ErrOffline
+	}
+
+	c.Outbox.mu.Lock()
+	defer c.Outbox.mu.Unlock()
+
+	entries, err := c.Outbox.read()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{Results: []ReplayResult{}}
+	var replayErr error
+	remaining := []OutboxEntry{}
+	for _, entry := range entries {
+		if replayErr != nil {
+			remaining = append(remaining, entry)
+			report.Results = append(report.Results, ReplayResult{Entry: entry, Outcome: ReplayPending})
+			continue
+		}
+
+		result, err := c.replayEntry(entry, policy)
+		if err != nil {
+			replayErr = err
+			remaining = append(remaining, entry)
+			result.Outcome = ReplayPending
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if len(remaining) < len(entries) {
+		c.RecordCache.Purge()
+	}
+	if err := c.Outbox.rewrite(remaining); err != nil {
+		return report, err
+	}
+
+	return report, replayErr
+}
+
+// replayEntry - Checks a queued write for conflicts and sends it. An error means the write could not
+// be resolved and should stay queued; the outcome is then meaningless.
+func (c *Client) replayEntry(entry OutboxEntry, policy ConflictPolicy) (ReplayResult, error) {
+	result := ReplayResult{Entry: entry}
+	target := c.HostURL + entry.Path
+
+	if record := newRecordFor(entry.Path); record != nil && entry.Method != http.MethodPost && policy == ConflictSkip {
+		if entry.BaseVersion == "" {
+			result.Outcome = ReplayConflict
+			result.Error = "cannot check for conflicts: no base version was recorded"
+			return result, nil
+		}
+
+		current, err := c.getVersioned(target, record)
+		// A base version taken from RecordCache is a content hash, even when the server sends ETags
+		if err == nil && strings.HasPrefix(entry.BaseVersion, contentVersionPrefix) && !strings.HasPrefix(current, contentVersionPrefix) {
+			current, err = contentVersion(record)
+		}
+		var statusErr *StatusError
+		switch {
+		case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound && entry.Method == http.MethodDelete:
+			result.Outcome = ReplayApplied
+			return result, nil
+		case err != nil:
+			result.Error = err.Error()
+			return result, fmt.Errorf("checking %s %s: %w", entry.Method, entry.Path, err)
+		case current != entry.BaseVersion:
+			result.Outcome = ReplayConflict
+			result.Error = fmt.Sprintf("%v: expected version %s, got %s", ErrConflict, entry.BaseVersion, current)
+			return result, nil
+		}
+	}
+
+	var body io.Reader
+	if len(entry.Body) > 0 {
+		body = bytes.NewReader(entry.Body)
+	}
+	req, err := http.NewRequest(entry.Method, target, body)
+	if err != nil {
+		result.Outcome = ReplayFailed
+		result.Error = err.Error()
+		return result, nil
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if _, _, err := c.send(req); err != nil {
+		result.Outcome = ReplayFailed
+		result.Error = err.Error()
+		var statusErr *StatusError
+		if unreachable(err) || errors.As(err, &statusErr) && statusErr.StatusCode >= 500 {
+			return result, err
+		}
+		return result, nil
+	}
+
+	result.Outcome = ReplayApplied
+	return result, nil
+}
+
+// unreachable - Reports whether err means a request never reached the server: the connection
+// could not be made because the host did not resolve or refused it
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// newRecordFor - Returns an empty record of the type served at path, or nil if unknown
+func newRecordFor(path string) interface{} {
+	switch {
+	case strings.HasPrefix(path, "/user"):
+		return &User{}
+	case strings.HasPrefix(path, "/products"):
+		return &Product{}
+	}
+
+	return nil
+}
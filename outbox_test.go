@@ -0,0 +1,272 @@
+// outbox_test.go
+
+package mockclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestOutbox_QueueAndReplay tests that writes to an unreachable server are queued and replayed in order
+func TestOutbox_QueueAndReplay(t *testing.T) {
+	var writes []string
+	// Create a mock server that accepts every write
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes = append(writes, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "name": "Alice", "price": "1.00", "stock": "1"}`))
+		}
+	}))
+	defer server.Close()
+
+	unreachable := "http://127.0.0.1:1"
+	client, _ := NewClient(&unreachable)
+	client.Outbox = NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+	if _, err := client.CreateUser(&User{Name: "Alice"}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued from CreateUser, got %v", err)
+	}
+	if _, err := client.UpdateProduct(&Product{ID: 7, Stock: 3}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued from UpdateProduct, got %v", err)
+	}
+	if err := client.DeleteUser(2); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued from DeleteUser, got %v", err)
+	}
+
+	entries, err := client.Outbox.Entries()
+	if err != nil {
+		t.Fatalf("expected no error reading outbox, got %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 queued writes, got %d", len(entries))
+	}
+
+	// Nothing was read before writing, so there are no base versions to check
+	client.HostURL = server.URL
+	report, err := client.ReplayOutbox(ConflictOverwrite)
+	if err != nil {
+		t.Fatalf("expected no error replaying, got %v", err)
+	}
+
+	expected := "POST /user,PATCH /products/7,DELETE /user/2"
+	if strings.Join(writes, ",") != expected {
+		t.Errorf("expected replayed requests %s, got %v", expected, writes)
+	}
+	for _, result := range report.Results {
+		if result.Outcome != ReplayApplied {
+			t.Errorf("expected %s to be applied, got %s (%s)", result.Entry.Path, result.Outcome, result.Error)
+		}
+	}
+	if entries, _ := client.Outbox.Entries(); len(entries) != 0 {
+		t.Errorf("expected empty outbox after replay, got %d entries", len(entries))
+	}
+}
+
+// TestOutbox_ReplayConflict tests that a record changed since it was cached is not overwritten
+func TestOutbox_ReplayConflict(t *testing.T) {
+	stock := "10"
+	writes := 0
+	// Create a mock server whose product changes while the client is offline
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes++
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "` + stock + `"}`))
+	}))
+	defer server.Close()
+
+	cache, _ := NewDiskCache(t.TempDir())
+	client, _ := NewClient(&server.URL)
+	client.DiskCache = cache
+	client.Outbox = NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+	client.GetProductByID(1)
+	client.Offline = true
+	if _, err := client.UpdateProduct(&Product{ID: 1, Name: "Ball", Price: 5, Stock: 9}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued, got %v", err)
+	}
+
+	stock = "4"
+	client.Offline = false
+	report, err := client.ReplayOutbox(ConflictSkip)
+	if err != nil {
+		t.Fatalf("expected no error replaying, got %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Outcome != ReplayConflict {
+		t.Fatalf("expected a single conflict, got %+v", report.Results)
+	}
+	if writes != 0 {
+		t.Errorf("expected conflicting write not to be sent, got %d writes", writes)
+	}
+}
+
+// TestOutbox_ReplayStopsWhenUnreachable tests that replay keeps writes queued while the server is down
+func TestOutbox_ReplayStopsWhenUnreachable(t *testing.T) {
+	unreachable := "http://127.0.0.1:1"
+	client, _ := NewClient(&unreachable)
+	client.Outbox = NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+	client.DeleteUser(1)
+	client.DeleteUser(2)
+
+	report, err := client.ReplayOutbox(ConflictOverwrite)
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+	if len(report.Results) != 2 || report.Results[1].Outcome != ReplayPending {
+		t.Errorf("expected both writes to remain pending, got %+v", report.Results)
+	}
+	if entries, _ := client.Outbox.Entries(); len(entries) != 2 {
+		t.Errorf("expected 2 queued writes, got %d", len(entries))
+	}
+}
+
+// TestOutbox_TimeoutNotQueued tests that a write which may have reached the server is not queued
+func TestOutbox_TimeoutNotQueued(t *testing.T) {
+	var writes atomic.Int32
+	// Create a mock server that applies the write but answers too late
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "1", "name": "Alice"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.HTTPClient.Timeout = 20 * time.Millisecond
+	client.Outbox = NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+	_, err := client.CreateUser(&User{Name: "Alice"})
+	if err == nil || errors.Is(err, ErrQueued) {
+		t.Fatalf("expected the timeout error, got %v", err)
+	}
+	if entries, _ := client.Outbox.Entries(); len(entries) != 0 {
+		t.Errorf("expected nothing queued, got %+v", entries)
+	}
+	if n := writes.Load(); n != 1 {
+		t.Errorf("expected the write to be sent once, got %d", n)
+	}
+}
+
+// TestOutbox_ReplayConflictFromRecordCache tests conflict detection with only a RecordCache, and that
+// writes without a base version are not applied under ConflictSkip
+func TestOutbox_ReplayConflictFromRecordCache(t *testing.T) {
+	stock := "10"
+	var writes []string
+	// Create a mock server whose product changes while the client is offline
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "` + stock + `"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.RecordCache = NewRecordCache(0, 0)
+	client.Outbox = NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+	client.GetProductByID(1)
+	client.Offline = true
+	if _, err := client.UpdateProduct(&Product{ID: 1, Name: "Ball", Price: 5, Stock: 9}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued, got %v", err)
+	}
+	if err := client.DeleteProduct(2); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued, got %v", err)
+	}
+
+	entries, _ := client.Outbox.Entries()
+	if len(entries) != 2 || entries[0].BaseVersion == "" || entries[1].BaseVersion != "" {
+		t.Fatalf("expected a base version only for the product that was read, got %+v", entries)
+	}
+
+	stock = "4"
+	client.Offline = false
+	report, err := client.ReplayOutbox(ConflictSkip)
+	if err != nil {
+		t.Fatalf("expected no error replaying, got %v", err)
+	}
+	if len(report.Results) != 2 || report.Results[0].Outcome != ReplayConflict || report.Results[1].Outcome != ReplayConflict {
+		t.Fatalf("expected two conflicts, got %+v", report.Results)
+	}
+	if len(writes) != 0 {
+		t.Errorf("expected no writes to be sent, got %v", writes)
+	}
+
+	// Once the current version has been read, the write applies
+	client.GetProductByID(1)
+	client.Offline = true
+	client.UpdateProduct(&Product{ID: 1, Name: "Ball", Price: 5, Stock: 9})
+	client.Offline = false
+	report, err = client.ReplayOutbox(ConflictSkip)
+	if err != nil || len(report.Results) != 1 || report.Results[0].Outcome != ReplayApplied {
+		t.Errorf("expected the write to be applied, got %+v (%v)", report, err)
+	}
+}
+
+// TestOutbox_ReplayKeepsWritesOnServerErrors tests that a failed conflict check or a 5xx keeps the write queued
+func TestOutbox_ReplayKeepsWritesOnServerErrors(t *testing.T) {
+	failGet, failPatch := false, false
+	writes := 0
+	// Create a mock server that can fail reads and writes with 503
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes++
+		}
+		if (r.Method == http.MethodGet && failGet) || (r.Method != http.MethodGet && failPatch) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "10"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.RecordCache = NewRecordCache(0, 0)
+	client.Outbox = NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+	client.GetProductByID(1)
+	client.Offline = true
+	if _, err := client.UpdateProduct(&Product{ID: 1, Name: "Ball", Price: 5, Stock: 9}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected ErrQueued, got %v", err)
+	}
+	client.Offline = false
+
+	for _, fail := range []*bool{&failGet, &failPatch} {
+		failGet, failPatch = false, false
+		*fail = true
+		report, err := client.ReplayOutbox(ConflictSkip)
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected the 503 error, got %v", err)
+		}
+		if len(report.Results) != 1 || report.Results[0].Outcome != ReplayPending {
+			t.Errorf("expected the write to stay pending, got %+v", report.Results)
+		}
+		if entries, _ := client.Outbox.Entries(); len(entries) != 1 {
+			t.Errorf("expected the write to stay queued, got %d entries", len(entries))
+		}
+	}
+	if writes != 1 {
+		t.Errorf("expected only the failed PATCH to be sent, got %d writes", writes)
+	}
+
+	failGet, failPatch = false, false
+	report, err := client.ReplayOutbox(ConflictSkip)
+	if err != nil || report.Results[0].Outcome != ReplayApplied {
+		t.Errorf("expected the write to be applied once the server recovers, got %+v (%v)", report, err)
+	}
+}
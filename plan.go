@@ -0,0 +1,313 @@
+package mockclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DesiredState - The users and products that should exist on the server
+type DesiredState struct {
+	Users    []User    `json:"users,omitempty"`
+	Products []Product `json:"products,omitempty"`
+
+	// userFields and productFields hold, by index, the fields each record loaded from a file gave;
+	// other fields are left untouched, even when the file omits a product's price or stock
+	userFields    [][]string
+	productFields [][]string
+}
+
+// PlanAction - The kind of change a plan step makes
+type PlanAction string
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanUpdate PlanAction = "update"
+	PlanDelete PlanAction = "delete"
+)
+
+// PlanStep - A single change needed to reach the desired state
+type PlanStep struct {
+	Action   PlanAction    `json:"action"`
+	Resource string        `json:"resource"`
+	ID       int           `json:"id,omitempty"`
+	Label    string        `json:"label"`
+	User     *User         `json:"user,omitempty"`
+	Product  *Product      `json:"product,omitempty"`
+	Changes  []FieldChange `json:"changes,omitempty"`
+}
+
+// Plan - The ordered steps that reconcile the server with a desired state
+type Plan struct {
+	Steps []PlanStep `json:"steps"`
+}
+
+// PlanOptions - Controls how a plan is computed
+type PlanOptions struct {
+	// Prune deletes server records that match no desired record
+	Prune bool
+	// UserKey identifies users; defaults to UserFullNameKey
+	UserKey func(User) string
+	// ProductKey identifies products; defaults to ProductNameDepartmentKey
+	ProductKey func(Product) string
+}
+
+// LoadDesiredState - Reads a desired state from a JSON file.
+// Records use the API wire format (string id, price and stock); id may be omitted. Plan only
+// changes the fields a record gives, so omitting price or stock leaves the server's value alone.
+func LoadDesiredState(path string) (*DesiredState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := struct {
+		Users    []json.RawMessage `json:"users"`
+		Products []json.RawMessage `json:"products"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	state := &DesiredState{}
+	for i, rec := range raw.Users {
+		user := User{}
+		fields, err := decodeRecord(rec, &user)
+		if err != nil {
+			return nil, fmt.Errorf("%s: users[%d]: %w", path, i, err)
+		}
+		state.Users = append(state.Users, user)
+		state.userFields = append(state.userFields, fields)
+	}
+	for i, rec := range raw.Products {
+		product := Product{}
+		fields, err := decodeRecord(rec, &product)
+		if err != nil {
+			return nil, fmt.Errorf("%s: products[%d]: %w", path, i, err)
+		}
+		state.Products = append(state.Products, product)
+		state.productFields = append(state.productFields, fields)
+	}
+
+	return state, nil
+}
+
+// decodeRecord - Unmarshals a wire-format record that may lack the server-assigned or numeric fields,
+// returning the names of the fields it gives other than id and createdAt
+func decodeRecord(data []byte, out interface{}) ([]string, error) {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	given := []string{}
+	for name := range fields {
+		if name != "id" && name != "createdAt" {
+			given = append(given, name)
+		}
+	}
+
+	numeric := []string{"id"}
+	if _, ok := out.(*Product); ok {
+		numeric = append(numeric, "price", "stock")
+	}
+	for _, name := range numeric {
+		if _, ok := fields[name]; !ok {
+			fields[name] = "0"
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return given, json.Unmarshal(data, out)
+}
+
+// Plan - Computes the steps needed to make the server match state
+func (c *Client) Plan(state *DesiredState, opts PlanOptions) (*Plan, error) {
+	if opts.UserKey == nil {
+		opts.UserKey = UserFullNameKey
+	}
+	if opts.ProductKey == nil {
+		opts.ProductKey = ProductNameDepartmentKey
+	}
+
+	users, err := c.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	products, err := c.GetProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Steps: []PlanStep{}}
+
+	currentUsers, err := indexByKey(users, opts.UserKey, "users")
+	if err != nil {
+		return nil, err
+	}
+	desiredUsers := map[string]bool{}
+	for i := range state.Users {
+		desired := state.Users[i]
+		key := opts.UserKey(desired)
+		if desiredUsers[key] {
+			return nil, fmt.Errorf("desired state has duplicate user %s", userLabel(desired))
+		}
+		desiredUsers[key] = true
+
+		current, ok := currentUsers[key]
+		if !ok {
+			plan.Steps = append(plan.Steps, PlanStep{Action: PlanCreate, Resource: "user", Label: userLabel(desired), User: &desired})
+			continue
+		}
+		changes, err := diffFields(current, desired, fieldsAt(state.userFields, i))
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			plan.Steps = append(plan.Steps, PlanStep{Action: PlanUpdate, Resource: "user", ID: current.ID, Label: userLabel(current), User: &desired, Changes: changes})
+		}
+	}
+
+	currentProducts, err := indexByKey(products, opts.ProductKey, "products")
+	if err != nil {
+		return nil, err
+	}
+	desiredProducts := map[string]bool{}
+	for i := range state.Products {
+		desired := state.Products[i]
+		key := opts.ProductKey(desired)
+		if desiredProducts[key] {
+			return nil, fmt.Errorf("desired state has duplicate product %s", productLabel(desired))
+		}
+		desiredProducts[key] = true
+
+		current, ok := currentProducts[key]
+		if !ok {
+			plan.Steps = append(plan.Steps, PlanStep{Action: PlanCreate, Resource: "product", Label: productLabel(desired), Product: &desired})
+			continue
+		}
+		changes, err := diffFields(current, desired, fieldsAt(state.productFields, i))
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			plan.Steps = append(plan.Steps, PlanStep{Action: PlanUpdate, Resource: "product", ID: current.ID, Label: productLabel(current), Product: &desired, Changes: changes})
+		}
+	}
+
+	if opts.Prune {
+		for _, u := range users {
+			if !desiredUsers[opts.UserKey(u)] {
+				plan.Steps = append(plan.Steps, PlanStep{Action: PlanDelete, Resource: "user", ID: u.ID, Label: userLabel(u)})
+			}
+		}
+		for _, p := range products {
+			if !desiredProducts[opts.ProductKey(p)] {
+				plan.Steps = append(plan.Steps, PlanStep{Action: PlanDelete, Resource: "product", ID: p.ID, Label: productLabel(p)})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply - Executes the steps of a plan in order, stopping at the first failure
+func (c *Client) Apply(plan *Plan) error {
+	for _, step := range plan.Steps {
+		if err := c.applyStep(step); err != nil {
+			return fmt.Errorf("%s %s %s: %w", step.Action, step.Resource, step.Label, err)
+		}
+	}
+
+	return nil
+}
+
+// applyStep - Executes a single plan step
+func (c *Client) applyStep(step PlanStep) error {
+	var err error
+	switch {
+	case step.Resource == "user" && step.Action == PlanCreate:
+		_, err = c.CreateUser(step.User)
+	case step.Resource == "user" && step.Action == PlanUpdate:
+		err = c.patchRecord(fmt.Sprintf("%s/user/%d", c.HostURL, step.ID), step.Changes, &User{})
+	case step.Resource == "user" && step.Action == PlanDelete:
+		err = c.DeleteUser(step.ID)
+	case step.Resource == "product" && step.Action == PlanCreate:
+		_, err = c.CreateProduct(step.Product)
+	case step.Resource == "product" && step.Action == PlanUpdate:
+		err = c.patchRecord(fmt.Sprintf("%s/products/%d", c.HostURL, step.ID), step.Changes, &Product{})
+	case step.Resource == "product" && step.Action == PlanDelete:
+		err = c.DeleteProduct(step.ID)
+	default:
+		err = fmt.Errorf("unsupported step")
+	}
+	if err == nil && step.Action == PlanUpdate {
+		c.RecordCache.invalidate(step.Resource, step.ID)
+	}
+
+	return err
+}
+
+// Print - Writes a human-readable summary of the plan to w
+func (p *Plan) Print(w io.Writer) error {
+	var sb strings.Builder
+	counts := map[PlanAction]int{}
+	for _, step := range p.Steps {
+		counts[step.Action]++
+
+		symbol := map[PlanAction]string{PlanCreate: "+", PlanUpdate: "~", PlanDelete: "-"}[step.Action]
+		if step.ID != 0 {
+			fmt.Fprintf(&sb, "%s %s %d %s\n", symbol, step.Resource, step.ID, step.Label)
+		} else {
+			fmt.Fprintf(&sb, "%s %s %s\n", symbol, step.Resource, step.Label)
+		}
+		for _, change := range step.Changes {
+			fmt.Fprintf(&sb, "    %s: %q => %q\n", change.Field, fmt.Sprint(change.Old), fmt.Sprint(change.New))
+		}
+	}
+	fmt.Fprintf(&sb, "Plan: %d to create, %d to update, %d to delete.\n", counts[PlanCreate], counts[PlanUpdate], counts[PlanDelete])
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// indexByKey - Maps records by key, failing when two records share one
+func indexByKey[T any](records []T, key func(T) string, resource string) (map[string]T, error) {
+	index := map[string]T{}
+	for _, record := range records {
+		k := key(record)
+		if _, ok := index[k]; ok {
+			return nil, fmt.Errorf("%w: %s %q", ErrAmbiguousMatch, resource, strings.ReplaceAll(k, "\x00", " "))
+		}
+		index[k] = record
+	}
+
+	return index, nil
+}
+
+// fieldsAt - Returns the fields loaded for record i, or nil for records not loaded from a file
+func fieldsAt(fields [][]string, i int) []string {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return nil
+}
+
+// userLabel - Short human-readable name for a user
+func userLabel(u User) string {
+	return fmt.Sprintf("%q", strings.TrimSpace(u.Name+" "+u.LastName))
+}
+
+// productLabel - Short human-readable name for a product
+func productLabel(p Product) string {
+	if p.Department == "" {
+		return fmt.Sprintf("%q", p.Name)
+	}
+	return fmt.Sprintf("%q (%s)", p.Name, p.Department)
+}
@@ -0,0 +1,178 @@
+// plan_test.go
+
+package mockclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newPlanServer creates a mock server with two users and one product, recording write requests
+func newPlanServer(t *testing.T, writes *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			*writes = append(*writes, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "0", "price": "0", "stock": "0"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`[{"id": "1", "name": "John", "lastName": "Doe", "address": "123 Main St"}, {"id": "2", "name": "Jane", "lastName": "Smith"}]`))
+		case "/products":
+			w.Write([]byte(`[{"id": "5", "name": "Ball", "price": "5.00", "stock": "10", "department": "Toys"}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+// TestPlan tests computing a plan with creates, updates and pruned deletes
+func TestPlan(t *testing.T) {
+	var writes []string
+	server := newPlanServer(t, &writes)
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	state := &DesiredState{
+		Users: []User{
+			{Name: "John", LastName: "Doe", Address: "456 Elm St"},
+			{Name: "Alice", LastName: "Brown"},
+		},
+		Products: []Product{
+			{Name: "Ball", Department: "Toys", Price: 5, Stock: 10},
+		},
+	}
+
+	plan, err := client.Plan(state, PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var out strings.Builder
+	if err := plan.Print(&out); err != nil {
+		t.Fatalf("expected no error printing plan, got %v", err)
+	}
+	expected := `~ user 1 "John Doe"
+    address: "123 Main St" => "456 Elm St"
++ user "Alice Brown"
+- user 2 "Jane Smith"
+Plan: 1 to create, 1 to update, 1 to delete.
+`
+	if out.String() != expected {
+		t.Errorf("expected plan output:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if len(writes) != 0 {
+		t.Errorf("expected planning not to write, got %v", writes)
+	}
+}
+
+// TestPlan_NoPrune tests that unmanaged records are kept without Prune
+func TestPlan_NoPrune(t *testing.T) {
+	var writes []string
+	server := newPlanServer(t, &writes)
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	plan, err := client.Plan(&DesiredState{}, PlanOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(plan.Steps) != 0 {
+		t.Errorf("expected empty plan, got %+v", plan.Steps)
+	}
+}
+
+// TestApply tests that applying a plan issues the expected requests in order
+func TestApply(t *testing.T) {
+	var writes []string
+	server := newPlanServer(t, &writes)
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	state := &DesiredState{
+		Users: []User{{Name: "John", LastName: "Doe", Address: "456 Elm St"}},
+		Products: []Product{
+			{Name: "Ball", Department: "Toys", Price: 5, Stock: 10},
+			{Name: "Kite", Department: "Toys", Price: 12, Stock: 4},
+		},
+	}
+
+	plan, err := client.Plan(state, PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.Apply(plan); err != nil {
+		t.Fatalf("expected no error applying plan, got %v", err)
+	}
+
+	expected := []string{"PATCH /user/1", "POST /products", "DELETE /user/2"}
+	if strings.Join(writes, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected requests %v, got %v", expected, writes)
+	}
+}
+
+// TestLoadDesiredState tests reading a desired state file without IDs
+func TestLoadDesiredState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	data := `{"users": [{"name": "John", "lastName": "Doe"}], "products": [{"name": "Ball", "price": "5.50", "department": "Toys"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("expected no error writing file, got %v", err)
+	}
+
+	state, err := LoadDesiredState(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(state.Users) != 1 || state.Users[0].LastName != "Doe" {
+		t.Errorf("unexpected users: %+v", state.Users)
+	}
+	if len(state.Products) != 1 || state.Products[0].Price != 5.5 || state.Products[0].Stock != 0 {
+		t.Errorf("unexpected products: %+v", state.Products)
+	}
+}
+
+// TestPlan_PartialProductFromFile tests that fields a desired state file omits are not planned as changes
+func TestPlan_PartialProductFromFile(t *testing.T) {
+	var writes []string
+	server := newPlanServer(t, &writes)
+	defer server.Close()
+
+	tests := []struct {
+		product  string
+		expected string
+	}{
+		{`{"name": "Ball", "department": "Toys", "type": "Kids"}`, "type: <nil> => Kids"},
+		{`{"name": "Ball", "department": "Toys", "stock": "0"}`, "stock: 10 => 0"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(path, []byte(`{"products": [`+tt.product+`]}`), 0o644); err != nil {
+			t.Fatalf("expected no error writing file, got %v", err)
+		}
+		state, err := LoadDesiredState(path)
+		if err != nil {
+			t.Fatalf("expected no error loading state, got %v", err)
+		}
+
+		client, _ := NewClient(&server.URL)
+		plan, err := client.Plan(state, PlanOptions{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(plan.Steps) != 1 || len(plan.Steps[0].Changes) != 1 {
+			t.Fatalf("expected one step with one change for %s, got %+v", tt.product, plan.Steps)
+		}
+		c := plan.Steps[0].Changes[0]
+		got := fmt.Sprintf("%s: %v => %v", c.Field, c.Old, c.New)
+		if got != tt.expected {
+			t.Errorf("expected change %s for %s, got %s", tt.expected, tt.product, got)
+		}
+	}
+}
@@ -99,6 +99,11 @@ func (c *Client) GetProducts() ([]Product, error) {
 
 // GetProductByID - Get product by ID
 func (c *Client) GetProductByID(id int) (*Product, error) {
+	if cached, ok := c.RecordCache.get("product", id); ok {
+		product := cached.(Product)
+		return &product, nil
+	}
+
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/products/%d", c.HostURL, id), nil)
 	if err != nil {
 		return nil, err
@@ -115,6 +120,7 @@ func (c *Client) GetProductByID(id int) (*Product, error) {
 		return nil, err
 	}
 
+	c.RecordCache.put("product", product.ID, *product)
 	return product, nil
 }
 
@@ -173,6 +179,21 @@ func (c *Client) UpdateProduct(product *Product) (*Product, error) {
 		return nil, err
 	}
 
+	c.RecordCache.put("product", updatedProduct.ID, updatedProduct)
+
+	return &updatedProduct, nil
+}
+
+// PatchProduct - Sets only the given fields of a product, by JSON name with wire values (price and
+// stock as strings); see PatchUser
+func (c *Client) PatchProduct(id int, fields map[string]string) (*Product, error) {
+	updatedProduct := Product{}
+	if err := c.patchRecord(fmt.Sprintf("%s/products/%d", c.HostURL, id), fieldChanges(fields), &updatedProduct); err != nil {
+		return nil, err
+	}
+
+	c.RecordCache.put("product", updatedProduct.ID, updatedProduct)
+
 	return &updatedProduct, nil
 }
 
@@ -188,5 +209,7 @@ func (c *Client) DeleteProduct(id int) error {
 		return err
 	}
 
+	c.RecordCache.invalidate("product", id)
+
 	return nil
 }
@@ -0,0 +1,152 @@
+package mockclient
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RecordCache - A read-through LRU cache of single records keyed by resource and ID.
+// All methods are safe to call on a nil cache, which caches nothing.
+type RecordCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	stats      CacheStats
+	now        func() time.Time
+}
+
+// CacheStats - Counters describing how a cache has been used
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// recordEntry - A cached record and when it expires
+type recordEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// NewRecordCache - Creates a cache whose entries live for ttl and which holds at most maxEntries records.
+// A zero ttl never expires entries and a zero maxEntries does not bound the size.
+func NewRecordCache(ttl time.Duration, maxEntries int) *RecordCache {
+	return &RecordCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      map[string]*list.Element{},
+		now:        time.Now,
+	}
+}
+
+// Stats - Returns a copy of the hit/miss/eviction counters
+func (rc *RecordCache) Stats() CacheStats {
+	if rc == nil {
+		return CacheStats{}
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.stats
+}
+
+// Len - Returns the number of cached records, including expired ones not yet evicted
+func (rc *RecordCache) Len() int {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.ll.Len()
+}
+
+// Purge - Removes every cached record
+func (rc *RecordCache) Purge() {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.ll.Init()
+	rc.items = map[string]*list.Element{}
+}
+
+// get - Returns the cached record for resource and id if present and fresh
+func (rc *RecordCache) get(resource string, id int) (interface{}, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	el, ok := rc.items[recordKey(resource, id)]
+	if !ok {
+		rc.stats.Misses++
+		return nil, false
+	}
+
+	entry := el.Value.(*recordEntry)
+	if rc.ttl > 0 && !rc.now().Before(entry.expires) {
+		rc.removeElement(el)
+		rc.stats.Misses++
+		return nil, false
+	}
+
+	rc.ll.MoveToFront(el)
+	rc.stats.Hits++
+	return entry.value, true
+}
+
+// put - Stores a record, evicting the least recently used one when full
+func (rc *RecordCache) put(resource string, id int, value interface{}) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	key := recordKey(resource, id)
+	expires := rc.now().Add(rc.ttl)
+	if el, ok := rc.items[key]; ok {
+		entry := el.Value.(*recordEntry)
+		entry.value = value
+		entry.expires = expires
+		rc.ll.MoveToFront(el)
+		return
+	}
+
+	rc.items[key] = rc.ll.PushFront(&recordEntry{key: key, value: value, expires: expires})
+	if rc.maxEntries > 0 && rc.ll.Len() > rc.maxEntries {
+		rc.removeElement(rc.ll.Back())
+		rc.stats.Evictions++
+	}
+}
+
+// invalidate - Drops the cached record for resource and id
+func (rc *RecordCache) invalidate(resource string, id int) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if el, ok := rc.items[recordKey(resource, id)]; ok {
+		rc.removeElement(el)
+	}
+}
+
+// removeElement - Unlinks an entry; the caller must hold mu
+func (rc *RecordCache) removeElement(el *list.Element) {
+	rc.ll.Remove(el)
+	delete(rc.items, el.Value.(*recordEntry).key)
+}
+
+// recordKey - Cache key for a record
+func recordKey(resource string, id int) string {
+	return fmt.Sprintf("%s/%d", resource, id)
+}
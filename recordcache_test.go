@@ -0,0 +1,112 @@
+// recordcache_test.go
+
+package mockclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRecordCache_GetUserByID tests that repeated reads are served from the cache
+func TestRecordCache_GetUserByID(t *testing.T) {
+	requests := 0
+	// Create a mock server counting requests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "John", "lastName": "Doe"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.RecordCache = NewRecordCache(time.Minute, 10)
+
+	for i := 0; i < 3; i++ {
+		user, err := client.GetUserByID(1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		user.Name = "Mutated"
+	}
+
+	user, _ := client.GetUserByID(1)
+	if user.Name != "John" {
+		t.Errorf("expected cached user name 'John', got '%s'", user.Name)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request to reach the server, got %d", requests)
+	}
+	stats := client.RecordCache.Stats()
+	if stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("expected 3 hits and 1 miss, got %+v", stats)
+	}
+}
+
+// TestRecordCache_WriteInvalidation tests that updates refresh and deletes drop cached records
+func TestRecordCache_WriteInvalidation(t *testing.T) {
+	requests := 0
+	// Create a mock server for a single product
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "10"}`))
+		case http.MethodPatch:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "9"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.RecordCache = NewRecordCache(0, 0)
+
+	client.GetProductByID(1)
+	if _, err := client.UpdateProduct(&Product{ID: 1, Name: "Ball", Price: 5, Stock: 9}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	product, _ := client.GetProductByID(1)
+	if product.Stock != 9 || requests != 2 {
+		t.Errorf("expected updated stock 9 from the cache after 2 requests, got stock %d after %d requests", product.Stock, requests)
+	}
+
+	if err := client.DeleteProduct(1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.RecordCache.Len() != 0 {
+		t.Errorf("expected delete to invalidate the cache, got %d entries", client.RecordCache.Len())
+	}
+}
+
+// TestRecordCache_TTLAndLRU tests expiry and least-recently-used eviction
+func TestRecordCache_TTLAndLRU(t *testing.T) {
+	now := time.Date(2024, 8, 5, 10, 0, 0, 0, time.UTC)
+	cache := NewRecordCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.put("user", 1, User{ID: 1})
+	cache.put("user", 2, User{ID: 2})
+	cache.get("user", 1)
+	cache.put("user", 3, User{ID: 3})
+
+	if _, ok := cache.get("user", 2); ok {
+		t.Errorf("expected least recently used user 2 to be evicted")
+	}
+	if _, ok := cache.get("user", 1); !ok {
+		t.Errorf("expected user 1 to still be cached")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.get("user", 3); ok {
+		t.Errorf("expected user 3 to have expired")
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 {
+		t.Errorf("expected 1 eviction, got %d", stats.Evictions)
+	}
+}
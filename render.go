@@ -0,0 +1,395 @@
+package mockclient
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Format - An output format understood by Render
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatYAML   Format = "yaml"
+)
+
+// Formats - All formats understood by Render
+var Formats = []Format{FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatYAML}
+
+var (
+	// ErrUnknownFormat - Returned by Render for a format it does not support
+	ErrUnknownFormat = errors.New("unknown format")
+	// ErrUnknownColumn - Returned by Render when a column or sort key is not a field of the records
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+// RenderOptions - How Render writes records
+type RenderOptions struct {
+	Format Format
+	// Columns selects and orders the fields to write by JSON name; empty means every field
+	Columns []string
+	// SortBy orders the records by a field's JSON name, descending when prefixed with "-"
+	SortBy string
+	// Template, when set, replaces Format: it is executed once per record, followed by a newline,
+	// with the record (e.g. a User) as data
+	Template string
+}
+
+// column - A struct field named by its JSON tag
+type column struct {
+	name  string
+	index int
+}
+
+// Render - Writes a User, Product, or slice of either (or any struct with JSON tags) to w.
+// Field names are the JSON names. JSON, NDJSON and YAML use the records' JSON encoding, so ids,
+// prices and stock appear as strings and empty fields tagged omitempty are left out; table and
+// CSV show every selected column with plain values. A single record renders as a JSON object,
+// a slice as an array.
+func Render(w io.Writer, records interface{}, opts RenderOptions) error {
+	v := reflect.ValueOf(records)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	single := v.Kind() == reflect.Struct
+	var rows []reflect.Value
+	switch {
+	case single:
+		rows = []reflect.Value{v}
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if row := reflect.Indirect(v.Index(i)); row.IsValid() {
+				rows = append(rows, row)
+			}
+		}
+	default:
+		return fmt.Errorf("cannot render %T", records)
+	}
+
+	elem := v.Type()
+	if !single {
+		elem = v.Type().Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot render %T", records)
+	}
+
+	all := columnsOf(elem)
+	cols, err := selectColumns(all, opts.Columns)
+	if err != nil {
+		return err
+	}
+	if opts.SortBy != "" {
+		if err := sortRows(rows, all, opts.SortBy); err != nil {
+			return err
+		}
+	}
+
+	if opts.Template != "" {
+		return renderTemplate(w, rows, opts.Template)
+	}
+
+	switch opts.Format {
+	case FormatTable, "":
+		return renderTable(w, rows, cols)
+	case FormatCSV:
+		return renderCSV(w, rows, cols)
+	case FormatJSON:
+		return renderJSON(w, rows, cols, single)
+	case FormatNDJSON:
+		return renderNDJSON(w, rows, cols)
+	case FormatYAML:
+		return renderYAML(w, rows, cols)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, opts.Format)
+}
+
+// ColumnNames - Returns the JSON names of the fields of a record type, in declaration order
+func ColumnNames(record interface{}) []string {
+	t := reflect.TypeOf(record)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	names := []string{}
+	for _, c := range columnsOf(t) {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// columnsOf - Returns the exported fields of t that are encoded to JSON
+func columnsOf(t reflect.Type) []column {
+	cols := []column{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		cols = append(cols, column{name: name, index: i})
+	}
+	return cols
+}
+
+// selectColumns - Returns the named columns in the order given, or all of them when names is empty
+func selectColumns(all []column, names []string) ([]column, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	cols := []column{}
+	for _, name := range names {
+		c, ok := findColumn(all, name)
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownColumn, name)
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// findColumn - Looks up a column by JSON name
+func findColumn(all []column, name string) (column, bool) {
+	for _, c := range all {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// sortRows - Stably sorts rows by a column, descending when key starts with "-"
+func sortRows(rows []reflect.Value, all []column, key string) error {
+	desc := strings.HasPrefix(key, "-")
+	c, ok := findColumn(all, strings.TrimPrefix(key, "-"))
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownColumn, strings.TrimPrefix(key, "-"))
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].Field(c.index), rows[j].Field(c.index)
+		if desc {
+			a, b = b, a
+		}
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		}
+		return cellText(a) < cellText(b)
+	})
+	return nil
+}
+
+// cellText - Formats a field value for table and CSV output
+func cellText(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// tableCleaner - Replaces the tabs and newlines that would break table alignment
+var tableCleaner = strings.NewReplacer("\t", " ", "\n", " ")
+
+// renderTable - Writes rows as space-aligned columns under a header of upper-cased JSON names
+func renderTable(w io.Writer, rows []reflect.Value, cols []column) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c.name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			cells[i] = tableCleaner.Replace(cellText(row.Field(c.index)))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// renderCSV - Writes rows as CSV with a header of JSON names
+func renderCSV(w io.Writer, rows []reflect.Value, cols []column) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	cw.Write(header)
+	for _, row := range rows {
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			cells[i] = cellText(row.Field(c.index))
+		}
+		cw.Write(cells)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// wireFields - Returns the selected fields of a row's JSON encoding in column order, skipping omitted ones
+func wireFields(row reflect.Value, cols []column) ([]string, []json.RawMessage, error) {
+	data, err := json.Marshal(row.Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+	encoded := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, nil, err
+	}
+
+	names, values := []string{}, []json.RawMessage{}
+	for _, c := range cols {
+		if v, ok := encoded[c.name]; ok {
+			names, values = append(names, c.name), append(values, v)
+		}
+	}
+	return names, values, nil
+}
+
+// wireObject - Encodes the selected fields of a row as a compact JSON object in column order
+func wireObject(row reflect.Value, cols []column) ([]byte, error) {
+	names, values, err := wireFields(row, cols)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(values[i])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// renderJSON - Writes an indented JSON array, or a single object
+func renderJSON(w io.Writer, rows []reflect.Value, cols []column, single bool) error {
+	var buf bytes.Buffer
+	if !single {
+		buf.WriteByte('[')
+	}
+	for i, row := range rows {
+		obj, err := wireObject(row, cols)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(obj)
+	}
+	if !single {
+		buf.WriteByte(']')
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// renderNDJSON - Writes one compact JSON object per line
+func renderNDJSON(w io.Writer, rows []reflect.Value, cols []column) error {
+	for _, row := range rows {
+		obj, err := wireObject(row, cols)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderYAML - Writes a YAML sequence of mappings. Values are the JSON-encoded field values,
+// which are valid YAML double-quoted scalars.
+func renderYAML(w io.Writer, rows []reflect.Value, cols []column) error {
+	if len(rows) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, row := range rows {
+		names, values, err := wireFields(row, cols)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			buf.WriteString("- {}\n")
+			continue
+		}
+		for i, name := range names {
+			prefix := "  "
+			if i == 0 {
+				prefix = "- "
+			}
+			fmt.Fprintf(&buf, "%s%s: %s\n", prefix, name, values[i])
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// renderTemplate - Executes a text/template once per row
+func renderTemplate(w io.Writer, rows []reflect.Value, text string) error {
+	tmpl, err := template.New("record").Parse(text)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := tmpl.Execute(w, row.Interface()); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
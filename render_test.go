@@ -0,0 +1,115 @@
+// render_test.go
+
+package mockclient
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var renderProducts = []Product{
+	{ID: 2, Name: "Kite", Price: 12.5, Stock: 0, Department: "Toys"},
+	{ID: 1, Name: "Ball", Price: 5, Stock: 10, Type: "Ball", Department: "Toys"},
+}
+
+// TestRender tests each output format with column selection and sorting
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     RenderOptions
+		expected string
+	}{
+		{
+			name: "table",
+			opts: RenderOptions{Format: FormatTable, Columns: []string{"id", "name", "price"}, SortBy: "id"},
+			expected: "ID  NAME  PRICE\n" +
+				"1   Ball  5\n" +
+				"2   Kite  12.5\n",
+		},
+		{
+			name:     "csv",
+			opts:     RenderOptions{Format: FormatCSV, Columns: []string{"name", "stock", "type"}, SortBy: "-stock"},
+			expected: "name,stock,type\nBall,10,Ball\nKite,0,\n",
+		},
+		{
+			name:     "ndjson",
+			opts:     RenderOptions{Format: FormatNDJSON, Columns: []string{"id", "price", "type"}},
+			expected: "{\"id\":\"2\",\"price\":\"12.500000\"}\n{\"id\":\"1\",\"price\":\"5.000000\",\"type\":\"Ball\"}\n",
+		},
+		{
+			name:     "json",
+			opts:     RenderOptions{Format: FormatJSON, Columns: []string{"name"}, SortBy: "name"},
+			expected: "[\n  {\n    \"name\": \"Ball\"\n  },\n  {\n    \"name\": \"Kite\"\n  }\n]\n",
+		},
+		{
+			name: "yaml",
+			opts: RenderOptions{Format: FormatYAML, Columns: []string{"id", "name", "type"}},
+			expected: "- id: \"2\"\n  name: \"Kite\"\n" +
+				"- id: \"1\"\n  name: \"Ball\"\n  type: \"Ball\"\n",
+		},
+		{
+			name:     "template",
+			opts:     RenderOptions{Template: "{{.Name}} costs {{printf \"%.2f\" .Price}}", SortBy: "-price"},
+			expected: "Kite costs 12.50\nBall costs 5.00\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := append([]Product(nil), renderProducts...)
+			var buf bytes.Buffer
+			if err := Render(&buf, records, tt.opts); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+// TestRender_SingleRecord tests that a single record renders as a JSON object in the wire format
+func TestRender_SingleRecord(t *testing.T) {
+	var buf bytes.Buffer
+	user := &User{ID: 3, Name: "Alice"}
+	if err := Render(&buf, user, RenderOptions{Format: FormatJSON}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "{\n  \"id\": \"3\",\n  \"name\": \"Alice\"\n}\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestRender_Errors tests unknown formats and columns
+func TestRender_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, renderProducts, RenderOptions{Format: "xml"}); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+	if err := Render(&buf, renderProducts, RenderOptions{Columns: []string{"color"}}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("expected ErrUnknownColumn, got %v", err)
+	}
+	if err := Render(&buf, renderProducts, RenderOptions{SortBy: "-color"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("expected ErrUnknownColumn, got %v", err)
+	}
+	if err := Render(&buf, 42, RenderOptions{}); err == nil {
+		t.Errorf("expected an error for a non-record value")
+	}
+}
+
+// TestColumnNames tests that column names follow the JSON tags
+func TestColumnNames(t *testing.T) {
+	names := ColumnNames([]User{})
+	expected := []string{"id", "name", "lastName", "address", "favoriteDogBreed", "createdAt"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package mockclient
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ResponseCache - Stores validated GET responses so they can be revalidated with conditional requests
+type ResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+// cachedResponse - A GET response body together with its validators
+type cachedResponse struct {
+	etag         string
+	lastModified string
+	body         []byte
+	header       http.Header
+}
+
+// NewResponseCache - Creates an empty response cache
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{entries: map[string]cachedResponse{}}
+}
+
+// Len - Returns the number of cached responses
+func (rc *ResponseCache) Len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.entries)
+}
+
+// Clear - Removes all cached responses
+func (rc *ResponseCache) Clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = map[string]cachedResponse{}
+}
+
+// prepare - Adds If-None-Match/If-Modified-Since to req when a cached response exists for its URL
+func (rc *ResponseCache) prepare(req *http.Request) {
+	rc.mu.Lock()
+	entry, ok := rc.entries[req.URL.String()]
+	rc.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// lookup - Returns the cached response for req
+func (rc *ResponseCache) lookup(req *http.Request) (cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[req.URL.String()]
+	return entry, ok
+}
+
+// store - Caches a successful response when it carries a validator, otherwise drops any stale entry
+func (rc *ResponseCache) store(req *http.Request, body []byte, header http.Header) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	key := req.URL.String()
+	etag, lastModified := header.Get("ETag"), header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		delete(rc.entries, key)
+		return
+	}
+
+	rc.entries[key] = cachedResponse{
+		etag:         etag,
+		lastModified: lastModified,
+		body:         append([]byte(nil), body...),
+		header:       header.Clone(),
+	}
+}
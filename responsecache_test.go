@@ -0,0 +1,91 @@
+// responsecache_test.go
+
+package mockclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestResponseCache_ETag tests that a 304 response is served from the cache
+func TestResponseCache_ETag(t *testing.T) {
+	requests := 0
+	// Create a mock server that answers 304 when the ETag matches
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Product 1", "price": "10.00", "stock": "50"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.ResponseCache = NewResponseCache()
+
+	for i := 0; i < 2; i++ {
+		product, err := client.GetProductByID(1)
+		if err != nil {
+			t.Fatalf("request %d: expected no error, got %v", i, err)
+		}
+		if product.Name != "Product 1" {
+			t.Errorf("request %d: expected product name 'Product 1', got %s", i, product.Name)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", requests)
+	}
+	if client.ResponseCache.Len() != 1 {
+		t.Errorf("expected 1 cached response, got %d", client.ResponseCache.Len())
+	}
+}
+
+// TestResponseCache_LastModified tests revalidation with If-Modified-Since
+func TestResponseCache_LastModified(t *testing.T) {
+	lastModified := "Mon, 05 Aug 2024 10:00:00 GMT"
+	// Create a mock server that only supplies Last-Modified
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "name": "John"}]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	client.ResponseCache = NewResponseCache()
+
+	if _, err := client.GetUsers(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	users, err := client.GetUsers()
+	if err != nil {
+		t.Fatalf("expected no error on revalidation, got %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "John" {
+		t.Errorf("expected cached user John, got %+v", users)
+	}
+}
+
+// TestDoRequest_NotModifiedWithoutCache tests that a 304 without a cache is still an error
+func TestDoRequest_NotModifiedWithoutCache(t *testing.T) {
+	// Create a mock server that always answers 304
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	_, err := client.GetUsers()
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+}
@@ -0,0 +1,166 @@
+package mockclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotStubbed is returned by Stub methods whose behavior has not been configured
+var ErrNotStubbed = errors.New("stub: method not configured")
+
+// StubCall - A recorded call to a Stub method
+type StubCall struct {
+	Method string
+	Args   []interface{}
+}
+
+// Stub - A configurable API implementation for unit tests.
+// Each method calls the matching Func field when set and returns ErrNotStubbed otherwise; every call is recorded.
+type Stub struct {
+	GetUsersFunc       func() ([]User, error)
+	GetUserByIDFunc    func(int) (*User, error)
+	CreateUserFunc     func(*User) (*User, error)
+	UpdateUserFunc     func(*User) (*User, error)
+	DeleteUserFunc     func(int) error
+	GetProductsFunc    func() ([]Product, error)
+	GetProductByIDFunc func(int) (*Product, error)
+	CreateProductFunc  func(*Product) (*Product, error)
+	UpdateProductFunc  func(*Product) (*Product, error)
+	DeleteProductFunc  func(int) error
+
+	mu    sync.Mutex
+	calls []StubCall
+}
+
+var _ API = (*Stub)(nil)
+
+// Calls - Returns every recorded call in order
+func (s *Stub) Calls() []StubCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StubCall(nil), s.calls...)
+}
+
+// CallsTo - Returns the recorded calls to method in order
+func (s *Stub) CallsTo(method string) []StubCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls := []StubCall{}
+	for _, call := range s.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset - Forgets the recorded calls
+func (s *Stub) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+// record - Appends a call
+func (s *Stub) record(method string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, StubCall{Method: method, Args: args})
+}
+
+// notStubbed - Error for an unconfigured method
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
+
+// GetUsers - Records the call and runs GetUsersFunc
+func (s *Stub) GetUsers() ([]User, error) {
+	s.record("GetUsers")
+	if s.GetUsersFunc == nil {
+		return nil, notStubbed("GetUsers")
+	}
+	return s.GetUsersFunc()
+}
+
+// GetUserByID - Records the call and runs GetUserByIDFunc
+func (s *Stub) GetUserByID(id int) (*User, error) {
+	s.record("GetUserByID", id)
+	if s.GetUserByIDFunc == nil {
+		return nil, notStubbed("GetUserByID")
+	}
+	return s.GetUserByIDFunc(id)
+}
+
+// CreateUser - Records the call and runs CreateUserFunc
+func (s *Stub) CreateUser(user *User) (*User, error) {
+	s.record("CreateUser", user)
+	if s.CreateUserFunc == nil {
+		return nil, notStubbed("CreateUser")
+	}
+	return s.CreateUserFunc(user)
+}
+
+// UpdateUser - Records the call and runs UpdateUserFunc
+func (s *Stub) UpdateUser(user *User) (*User, error) {
+	s.record("UpdateUser", user)
+	if s.UpdateUserFunc == nil {
+		return nil, notStubbed("UpdateUser")
+	}
+	return s.UpdateUserFunc(user)
+}
+
+// DeleteUser - Records the call and runs DeleteUserFunc
+func (s *Stub) DeleteUser(id int) error {
+	s.record("DeleteUser", id)
+	if s.DeleteUserFunc == nil {
+		return notStubbed("DeleteUser")
+	}
+	return s.DeleteUserFunc(id)
+}
+
+// GetProducts - Records the call and runs GetProductsFunc
+func (s *Stub) GetProducts() ([]Product, error) {
+	s.record("GetProducts")
+	if s.GetProductsFunc == nil {
+		return nil, notStubbed("GetProducts")
+	}
+	return s.GetProductsFunc()
+}
+
+// GetProductByID - Records the call and runs GetProductByIDFunc
+func (s *Stub) GetProductByID(id int) (*Product, error) {
+	s.record("GetProductByID", id)
+	if s.GetProductByIDFunc == nil {
+		return nil, notStubbed("GetProductByID")
+	}
+	return s.GetProductByIDFunc(id)
+}
+
+// CreateProduct - Records the call and runs CreateProductFunc
+func (s *Stub) CreateProduct(product *Product) (*Product, error) {
+	s.record("CreateProduct", product)
+	if s.CreateProductFunc == nil {
+		return nil, notStubbed("CreateProduct")
+	}
+	return s.CreateProductFunc(product)
+}
+
+// UpdateProduct - Records the call and runs UpdateProductFunc
+func (s *Stub) UpdateProduct(product *Product) (*Product, error) {
+	s.record("UpdateProduct", product)
+	if s.UpdateProductFunc == nil {
+		return nil, notStubbed("UpdateProduct")
+	}
+	return s.UpdateProductFunc(product)
+}
+
+// DeleteProduct - Records the call and runs DeleteProductFunc
+func (s *Stub) DeleteProduct(id int) error {
+	s.record("DeleteProduct", id)
+	if s.DeleteProductFunc == nil {
+		return notStubbed("DeleteProduct")
+	}
+	return s.DeleteProductFunc(id)
+}
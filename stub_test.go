@@ -0,0 +1,47 @@
+// stub_test.go
+
+package mockclient
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStub tests programmed results and call recording
+func TestStub(t *testing.T) {
+	notFound := errors.New("not found")
+	stub := &Stub{
+		GetUserByIDFunc: func(id int) (*User, error) {
+			if id == 1 {
+				return &User{ID: 1, Name: "John"}, nil
+			}
+			return nil, notFound
+		},
+	}
+
+	// Consumers depend on the interface rather than *Client
+	var users UsersAPI = stub
+	user, err := users.GetUserByID(1)
+	if err != nil || user.Name != "John" {
+		t.Errorf("expected user John and no error, got %+v and %v", user, err)
+	}
+	if _, err := users.GetUserByID(2); !errors.Is(err, notFound) {
+		t.Errorf("expected programmed error, got %v", err)
+	}
+	if err := stub.DeleteProduct(3); !errors.Is(err, ErrNotStubbed) {
+		t.Errorf("expected ErrNotStubbed, got %v", err)
+	}
+
+	calls := stub.CallsTo("GetUserByID")
+	if len(calls) != 2 || calls[0].Args[0] != 1 || calls[1].Args[0] != 2 {
+		t.Errorf("expected GetUserByID calls with 1 and 2, got %+v", calls)
+	}
+	if len(stub.Calls()) != 3 {
+		t.Errorf("expected 3 recorded calls, got %d", len(stub.Calls()))
+	}
+
+	stub.Reset()
+	if len(stub.Calls()) != 0 {
+		t.Errorf("expected no calls after Reset, got %d", len(stub.Calls()))
+	}
+}
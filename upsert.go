@@ -0,0 +1,250 @@
+package mockclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// UpsertAction - The action taken by an upsert operation
+type UpsertAction string
+
+const (
+	UpsertCreated   UpsertAction = "created"
+	UpsertUpdated   UpsertAction = "updated"
+	UpsertUnchanged UpsertAction = "unchanged"
+)
+
+// ErrAmbiguousMatch is returned when more than one record matches a natural key
+var ErrAmbiguousMatch = errors.New("multiple records match key")
+
+// FieldChange - A single field that differs between two records, using wire (JSON) values
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// UserFullNameKey - Natural key for users built from Name and LastName
+func UserFullNameKey(u User) string {
+	return u.Name + "\x00" + u.LastName
+}
+
+// ProductNameDepartmentKey - Natural key for products built from Name and Department
+func ProductNameDepartmentKey(p Product) string {
+	return p.Name + "\x00" + p.Department
+}
+
+// UpsertUser - Creates the user if no record matches key, otherwise patches the fields that differ
+func (c *Client) UpsertUser(user *User, key func(User) string) (*User, UpsertAction, error) {
+	users, err := c.GetUsers()
+	if err != nil {
+		return nil, "", err
+	}
+
+	want := key(*user)
+	var matches []User
+	for _, u := range users {
+		if key(u) == want {
+			matches = append(matches, u)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		created, err := c.CreateUser(user)
+		if err != nil {
+			return nil, "", err
+		}
+		return created, UpsertCreated, nil
+	case 1:
+	default:
+		return nil, "", fmt.Errorf("%w: %d users", ErrAmbiguousMatch, len(matches))
+	}
+
+	current := matches[0]
+	changes, err := diffRecords(current, *user)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(changes) == 0 {
+		return &current, UpsertUnchanged, nil
+	}
+
+	updated := User{}
+	if err := c.patchRecord(fmt.Sprintf("%s/user/%d", c.HostURL, current.ID), changes, &updated); err != nil {
+		return nil, "", err
+	}
+
+	c.RecordCache.put("user", updated.ID, updated)
+
+	return &updated, UpsertUpdated, nil
+}
+
+// UpsertProduct - Creates the product if no record matches key, otherwise patches the fields that differ
+func (c *Client) UpsertProduct(product *Product, key func(Product) string) (*Product, UpsertAction, error) {
+	products, err := c.GetProducts()
+	if err != nil {
+		return nil, "", err
+	}
+
+	want := key(*product)
+	var matches []Product
+	for _, p := range products {
+		if key(p) == want {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		created, err := c.CreateProduct(product)
+		if err != nil {
+			return nil, "", err
+		}
+		return created, UpsertCreated, nil
+	case 1:
+	default:
+		return nil, "", fmt.Errorf("%w: %d products", ErrAmbiguousMatch, len(matches))
+	}
+
+	current := matches[0]
+	changes, err := diffRecords(current, *product)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(changes) == 0 {
+		return &current, UpsertUnchanged, nil
+	}
+
+	updated := Product{}
+	if err := c.patchRecord(fmt.Sprintf("%s/products/%d", c.HostURL, current.ID), changes, &updated); err != nil {
+		return nil, "", err
+	}
+
+	c.RecordCache.put("product", updated.ID, updated)
+
+	return &updated, UpsertUpdated, nil
+}
+
+// patchRecord - Sends only the changed fields as a PATCH body and decodes the response into out
+func (c *Client) patchRecord(url string, changes []FieldChange, out interface{}) error {
+	fields := map[string]interface{}{}
+	for _, change := range changes {
+		fields[change.Field] = change.New
+	}
+
+	rb, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PATCH", url, strings.NewReader(string(rb)))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// fieldChanges - Turns field values into the changes patchRecord sends
+func fieldChanges(fields map[string]string) []FieldChange {
+	changes := []FieldChange{}
+	for field, value := range fields {
+		changes = append(changes, FieldChange{Field: field, New: value})
+	}
+	return changes
+}
+
+// recordFields - Returns the wire representation of a record as a map, without server-managed fields
+func recordFields(record interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "id")
+	delete(fields, "createdAt")
+	return fields, nil
+}
+
+// diffRecords - Lists the fields set on desired whose values differ from current, sorted by field name.
+// Fields left at their zero value on desired are not set and are left untouched; this includes a
+// product's Price and Stock, which are always present in its JSON.
+func diffRecords(current, desired interface{}) ([]FieldChange, error) {
+	return diffFields(current, desired, nil)
+}
+
+// diffFields - Lists the named fields whose values differ between current and desired, sorted by
+// field name; nil fields means the fields set on desired, as in diffRecords
+func diffFields(current, desired interface{}, fields []string) ([]FieldChange, error) {
+	have, err := recordFields(current)
+	if err != nil {
+		return nil, err
+	}
+	want, err := recordFields(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields == nil {
+		if p, ok := desired.(Product); ok {
+			if p.Price == 0 {
+				delete(want, "price")
+			}
+			if p.Stock == 0 {
+				delete(want, "stock")
+			}
+		}
+		fields = sortedKeys(want)
+	} else {
+		// Fields without a wire value on desired, such as empty user fields, are never cleared
+		named := []string{}
+		for _, field := range fields {
+			if _, ok := want[field]; ok {
+				named = append(named, field)
+			}
+		}
+		sort.Strings(named)
+		fields = named
+	}
+
+	return compareFields(have, want, fields), nil
+}
+
+// compareFields - Lists the given fields whose values differ between have and want
+func compareFields(have, want map[string]interface{}, fields []string) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range fields {
+		if have[field] != want[field] {
+			changes = append(changes, FieldChange{Field: field, Old: have[field], New: want[field]})
+		}
+	}
+
+	return changes
+}
+
+// sortedKeys - Returns the keys of m in ascending order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+// upsert_test.go
+
+package mockclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUpsertUser_Create tests UpsertUser when no user matches the key
+func TestUpsertUser_Create(t *testing.T) {
+	// Create a mock server with no matching user
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "1", "name": "Jane", "lastName": "Smith"}]`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "2", "name": "John", "lastName": "Doe", "address": "123 Main St"}`))
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	user, action, err := client.UpsertUser(&User{Name: "John", LastName: "Doe", Address: "123 Main St"}, UserFullNameKey)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if action != UpsertCreated {
+		t.Errorf("expected action %q, got %q", UpsertCreated, action)
+	}
+	if user.ID != 2 {
+		t.Errorf("expected user ID to be 2, got %d", user.ID)
+	}
+}
+
+// TestUpsertUser_PatchesDifferingFields tests UpsertUser sends only the changed fields
+func TestUpsertUser_PatchesDifferingFields(t *testing.T) {
+	// Create a mock server with one matching user
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "1", "name": "John", "lastName": "Doe", "address": "123 Main St", "favoriteDogBreed": "Beagle"}]`))
+		case http.MethodPatch:
+			if r.URL.Path != "/user/1" {
+				t.Errorf("expected request to /user/1, got %s", r.URL.Path)
+			}
+
+			var fields map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+				t.Fatalf("expected no error decoding request body, got %v", err)
+			}
+			if len(fields) != 1 || fields["address"] != "456 Elm St" {
+				t.Errorf("expected only address in patch body, got %v", fields)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "name": "John", "lastName": "Doe", "address": "456 Elm St", "favoriteDogBreed": "Beagle"}`))
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	user, action, err := client.UpsertUser(&User{Name: "John", LastName: "Doe", Address: "456 Elm St"}, UserFullNameKey)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if action != UpsertUpdated {
+		t.Errorf("expected action %q, got %q", UpsertUpdated, action)
+	}
+	if user.Address != "456 Elm St" {
+		t.Errorf("expected address to be '456 Elm St', got '%s'", user.Address)
+	}
+}
+
+// TestUpsertProduct_Unchanged tests UpsertProduct does not write when nothing differs
+func TestUpsertProduct_Unchanged(t *testing.T) {
+	// Create a mock server that only expects a list request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "7", "name": "Ball", "price": "5.00", "stock": "10", "department": "Toys"}]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	product, action, err := client.UpsertProduct(&Product{Name: "Ball", Department: "Toys", Price: 5, Stock: 10}, ProductNameDepartmentKey)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if action != UpsertUnchanged {
+		t.Errorf("expected action %q, got %q", UpsertUnchanged, action)
+	}
+	if product.ID != 7 {
+		t.Errorf("expected product ID to be 7, got %d", product.ID)
+	}
+}
+
+// TestUpsertProduct_KeepsUnsetPriceAndStock tests UpsertProduct leaves price and stock alone when they are not set
+func TestUpsertProduct_KeepsUnsetPriceAndStock(t *testing.T) {
+	// Create a mock server with one matching product that has a price and stock
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "7", "name": "Ball", "price": "5.00", "stock": "10", "department": "Toys"}]`))
+		case http.MethodPatch:
+			var fields map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+				t.Fatalf("expected no error decoding request body, got %v", err)
+			}
+			if len(fields) != 1 || fields["type"] != "Kids" {
+				t.Errorf("expected only type in patch body, got %v", fields)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "7", "name": "Ball", "price": "5.00", "stock": "10", "type": "Kids", "department": "Toys"}`))
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	product, action, err := client.UpsertProduct(&Product{Name: "Ball", Department: "Toys", Type: "Kids"}, ProductNameDepartmentKey)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if action != UpsertUpdated {
+		t.Errorf("expected action %q, got %q", UpsertUpdated, action)
+	}
+	if product.Price != 5 || product.Stock != 10 {
+		t.Errorf("expected price 5 and stock 10 to be kept, got %v and %d", product.Price, product.Stock)
+	}
+}
+
+// TestUpsertProduct_Ambiguous tests UpsertProduct with several records matching the key
+func TestUpsertProduct_Ambiguous(t *testing.T) {
+	// Create a mock server with duplicate products
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "name": "Ball", "price": "5.00", "stock": "10", "department": "Toys"}, {"id": "2", "name": "Ball", "price": "6.00", "stock": "3", "department": "Toys"}]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	_, _, err := client.UpsertProduct(&Product{Name: "Ball", Department: "Toys"}, ProductNameDepartmentKey)
+	if !errors.Is(err, ErrAmbiguousMatch) {
+		t.Fatalf("expected ErrAmbiguousMatch, got %v", err)
+	}
+}
@@ -40,6 +40,11 @@ func (c *Client) GetUsers() ([]User, error) {
 
 // GetUserByID - Returns a user by ID (no auth required)
 func (c *Client) GetUserByID(id int) (*User, error) {
+	if cached, ok := c.RecordCache.get("user", id); ok {
+		user := cached.(User)
+		return &user, nil
+	}
+
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/user/%d", c.HostURL, id), nil)
 	if err != nil {
 		return nil, err
@@ -56,6 +61,7 @@ func (c *Client) GetUserByID(id int) (*User, error) {
 		return nil, err
 	}
 
+	c.RecordCache.put("user", user.ID, *user)
 	return user, nil
 }
 
@@ -155,6 +161,21 @@ func (c *Client) UpdateUser(user *User) (*User, error) {
 		return nil, err
 	}
 
+	c.RecordCache.put("user", updatedUser.ID, updatedUser)
+
+	return &updatedUser, nil
+}
+
+// PatchUser - Sets only the given fields of a user, by JSON name; other fields keep the values the
+// server has, so concurrent changes to them are not overwritten. An empty value clears a field.
+func (c *Client) PatchUser(id int, fields map[string]string) (*User, error) {
+	updatedUser := User{}
+	if err := c.patchRecord(fmt.Sprintf("%s/user/%d", c.HostURL, id), fieldChanges(fields), &updatedUser); err != nil {
+		return nil, err
+	}
+
+	c.RecordCache.put("user", updatedUser.ID, updatedUser)
+
 	return &updatedUser, nil
 }
 
@@ -170,5 +191,7 @@ func (c *Client) DeleteUser(id int) error {
 		return err
 	}
 
+	c.RecordCache.invalidate("user", id)
+
 	return nil
 }
@@ -380,6 +380,37 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+// TestPatchUser tests that PatchUser sends only the given fields, including empty ones
+func TestPatchUser(t *testing.T) {
+	// Setup a mock server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/user/1" {
+			t.Errorf("expected PATCH /user/1, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var fields map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			t.Fatalf("expected no error decoding request body, got %v", err)
+		}
+		if len(fields) != 2 || fields["name"] != "Bob" || fields["address"] != "" {
+			t.Errorf("expected only name and an empty address in patch body, got %v", fields)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Bob", "lastName": "Smith", "address": ""}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	user, err := client.PatchUser(1, map[string]string{"name": "Bob", "address": ""})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if user.LastName != "Smith" || user.Address != "" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+}
+
 // TestDeleteUser tests the DeleteUser method for a successful user deletion
 func TestDeleteUser(t *testing.T) {
 	// Setup a mock server
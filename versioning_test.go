@@ -0,0 +1,99 @@
+// versioning_test.go
+
+package mockclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUpdateProductIfMatch_ETag tests a conditional update when the server supplies an ETag
+func TestUpdateProductIfMatch_ETag(t *testing.T) {
+	// Create a mock server that versions the product with an ETag
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/products/1" {
+			t.Errorf("expected request to /products/1, got %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "10"}`))
+		case http.MethodPatch:
+			if r.Header.Get("If-Match") != `"v1"` {
+				t.Errorf("expected If-Match header %q, got %q", `"v1"`, r.Header.Get("If-Match"))
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "9"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	product, version, err := client.GetProductVersion(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if version != `"v1"` {
+		t.Errorf("expected version %q, got %q", `"v1"`, version)
+	}
+
+	product.Stock--
+	updated, err := client.UpdateProductIfMatch(product, version)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Stock != 9 {
+		t.Errorf("expected stock to be 9, got %d", updated.Stock)
+	}
+}
+
+// TestUpdateProductIfMatch_Conflict tests a conditional update when the record changed in between
+func TestUpdateProductIfMatch_Conflict(t *testing.T) {
+	stock := "10"
+	// Create a mock server without ETags whose stock changes after the first read
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected no write, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "Ball", "price": "5.00", "stock": "` + stock + `"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	product, version, err := client.GetProductVersion(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	stock = "3"
+	product.Stock--
+	_, err = client.UpdateProductIfMatch(product, version)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+// TestUpdateUserIfMatch_PreconditionFailed tests that a 412 from the server is reported as a conflict
+func TestUpdateUserIfMatch_PreconditionFailed(t *testing.T) {
+	// Create a mock server that rejects the write
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		if r.Method == http.MethodPatch {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "1", "name": "John", "lastName": "Doe"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(&server.URL)
+	_, err := client.UpdateUserIfMatch(&User{ID: 1, Name: "Johnny"}, `"v2"`)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}